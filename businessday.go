@@ -0,0 +1,161 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// HolidayRule决定CalendarSchedule如何处理落在非工作日的激活时间。
+type HolidayRule int
+
+const (
+	SkipHoliday         HolidayRule = iota // 跳过该激活时间
+	NextBusinessDay                        // 推迟到之后第一个工作日的同一时刻
+	PreviousBusinessDay                    // 提前到之前最后一个工作日的同一时刻
+)
+
+// maxHolidayRun是移动激活时间时最多跳过的连续非工作日天数，
+// 防止每天都是非工作日的Calendar导致死循环。
+const maxHolidayRun = 366
+
+// CalendarSchedule按照Calendar调整另一个时间表的激活时间。
+type CalendarSchedule struct {
+	Schedule Schedule
+	Calendar *Calendar
+	Rule     HolidayRule
+}
+
+// OnBusinessDays返回只在cal的工作日激活的schedule。
+// 落在非工作日的激活时间按rule跳过，或者移动到相邻的工作日（保持时刻不变）。
+// 移动后与其他激活时间重合的激活时间只会运行一次。
+//
+// 示例
+//
+//  // 每月15日发工资，遇到节假日提前到之前的工作日
+//  sched, _ := cron.ParseStandard("0 9 15 * *")
+//  payroll := cron.OnBusinessDays(sched, holidays, cron.PreviousBusinessDay)
+//
+func OnBusinessDays(schedule Schedule, cal *Calendar, rule HolidayRule) CalendarSchedule {
+	return CalendarSchedule{schedule, cal, rule}
+}
+
+// Next返回调整后晚于t的最早激活时间。
+// 如果在五年内（或MaxActivations次尝试内）找不到，则返回时间的零值。
+func (s CalendarSchedule) Next(t time.Time) time.Time {
+	var (
+		limit = t.AddDate(compositeYears, 0, 0)
+		best  time.Time
+		next  = s.Schedule.Next(t)
+	)
+	for attempts := 0; attempts < MaxActivations && !next.IsZero() && !next.After(limit); attempts++ {
+		business := s.Calendar.IsBusinessDay(next)
+		switch s.Rule {
+		case SkipHoliday:
+			if business {
+				return next
+			}
+		case NextBusinessDay:
+			// 推迟后的时间不早于原来的时间，因此更晚的激活时间不会比best更早。
+			if !best.IsZero() && !next.Before(best) {
+				return best
+			}
+			if shifted := s.Calendar.shift(next, 1); !shifted.IsZero() && (best.IsZero() || shifted.Before(best)) {
+				best = shifted
+			}
+		case PreviousBusinessDay:
+			// 之后的激活时间最多提前到这个工作日，因此不会比best更早。
+			if business && !best.IsZero() && dateOf(next) != dateOf(best) {
+				return best
+			}
+			if shifted := s.Calendar.shift(next, -1); shifted.After(t) && (best.IsZero() || shifted.Before(best)) {
+				best = shifted
+			}
+		}
+		next = s.Schedule.Next(next)
+	}
+	return best
+}
+
+// shift将t按days（1或-1）逐日移动到最近的工作日，保持时刻不变。
+// 如果在maxHolidayRun天内都找不到工作日，则返回时间的零值。
+func (c *Calendar) shift(t time.Time, days int) time.Time {
+	for i := 0; i < maxHolidayRun; i++ {
+		if c.IsBusinessDay(t) {
+			return t
+		}
+		t = t.AddDate(0, 0, days)
+	}
+	return time.Time{}
+}
+
+// BusinessDaySchedule在每月的第N个工作日的给定时刻激活。
+type BusinessDaySchedule struct {
+	// N是工作日的序号，从1开始；负数表示从月末倒数，-1为最后一个工作日。
+	N int
+
+	// Hour和Minute是激活的时刻。
+	Hour, Minute int
+
+	Calendar *Calendar
+	Location *time.Location
+}
+
+// BusinessDay返回在每月（按cal计算的）第n个工作日的零点激活的时间表，时区为time.Local。
+// n为负数时从月末倒数，例如BusinessDay(-1, cal)表示每月最后一个工作日。
+func BusinessDay(n int, cal *Calendar) BusinessDaySchedule {
+	return BusinessDaySchedule{N: n, Calendar: cal, Location: time.Local}
+}
+
+// Next返回晚于t的下一个激活时间。如果五年内都没有第N个工作日，则返回时间的零值。
+func (s BusinessDaySchedule) Next(t time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = t.Location()
+	}
+	t = t.In(loc)
+
+	year, month, _ := t.Date()
+	for i := 0; i <= 12*compositeYears; i++ {
+		day, ok := s.day(year, month+time.Month(i), loc)
+		if !ok {
+			continue
+		}
+		if next := time.Date(day.Year(), day.Month(), day.Day(), s.Hour, s.Minute, 0, 0, loc); next.After(t) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// day返回给定月份的第N个工作日的零点。如果该月没有第N个工作日，则返回false。
+func (s BusinessDaySchedule) day(year int, month time.Month, loc *time.Location) (time.Time, bool) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	days := daysIn(first.Year(), first.Month())
+	count := 0
+	for i := 0; i < days; i++ {
+		d := i
+		if s.N < 0 {
+			d = days - 1 - i
+		}
+		day := time.Date(first.Year(), first.Month(), 1+d, 0, 0, 0, 0, loc)
+		if !s.Calendar.IsBusinessDay(day) {
+			continue
+		}
+		if count++; count == s.N || -count == s.N {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// String返回该时间表的描述符，例如"@businessday -1 18:00"。日历无法用描述符表示。
+func (s BusinessDaySchedule) String() string {
+	spec := fmt.Sprintf("@businessday %d", s.N)
+	if s.Hour != 0 || s.Minute != 0 {
+		spec += fmt.Sprintf(" %02d:%02d", s.Hour, s.Minute)
+	}
+	if s.Location != nil && s.Location != time.Local {
+		spec = "CRON_TZ=" + s.Location.String() + " " + spec
+	}
+	return spec
+}
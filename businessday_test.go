@@ -0,0 +1,133 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOnBusinessDays(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday(getTime("Wed Jul 4 00:00 2012"), "Independence Day")
+
+	daily, _ := ParseStandard("0 9 * * *")
+	fourth, _ := ParseStandard("0 9 4 * *")
+	third, _ := ParseStandard("0 9 3 * *")
+	fifthEarly, _ := ParseStandard("0 8 5 * *")
+	fourthEarly, _ := ParseStandard("0 8 4 * *")
+
+	tests := []struct {
+		schedule Schedule
+		time     string
+		expected string
+	}{
+		{OnBusinessDays(daily, cal, SkipHoliday), "Tue Jul 3 10:00 2012", "Thu Jul 5 09:00 2012"},
+		{OnBusinessDays(daily, cal, SkipHoliday), "Fri Jul 6 10:00 2012", "Mon Jul 9 09:00 2012"},
+		{OnBusinessDays(daily, cal, NextBusinessDay), "Fri Jul 6 10:00 2012", "Mon Jul 9 09:00 2012"},
+		{OnBusinessDays(daily, cal, NextBusinessDay), "Mon Jul 9 09:00 2012", "Tue Jul 10 09:00 2012"},
+		{OnBusinessDays(daily, cal, PreviousBusinessDay), "Fri Jul 6 10:00 2012", "Mon Jul 9 09:00 2012"},
+
+		{OnBusinessDays(fourth, cal, SkipHoliday), "Sun Jul 1 00:00 2012", "Tue Sep 4 09:00 2012"},
+		{OnBusinessDays(fourth, cal, NextBusinessDay), "Sun Jul 1 00:00 2012", "Thu Jul 5 09:00 2012"},
+		{OnBusinessDays(fourth, cal, PreviousBusinessDay), "Sun Jul 1 00:00 2012", "Tue Jul 3 09:00 2012"},
+		{OnBusinessDays(fourth, cal, PreviousBusinessDay), "Tue Jul 3 09:00 2012", "Fri Aug 3 09:00 2012"},
+
+		// A shifted activation may be overtaken by a later one.
+		{OnBusinessDays(Union(fourth, fifthEarly), cal, NextBusinessDay), "Sun Jul 1 00:00 2012", "Thu Jul 5 08:00 2012"},
+		{OnBusinessDays(Union(third, fourthEarly), cal, PreviousBusinessDay), "Sun Jul 1 00:00 2012", "Tue Jul 3 08:00 2012"},
+		{OnBusinessDays(Union(third, fourthEarly), cal, PreviousBusinessDay), "Tue Jul 3 08:00 2012", "Tue Jul 3 09:00 2012"},
+	}
+	for _, c := range tests {
+		actual := c.schedule.Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", c.time, expected, actual)
+		}
+	}
+
+	noBusinessDays := NewCalendar()
+	noBusinessDays.SetWeekend(0, 1, 2, 3, 4, 5, 6)
+	for _, rule := range []HolidayRule{SkipHoliday, NextBusinessDay, PreviousBusinessDay} {
+		if actual := OnBusinessDays(daily, noBusinessDays, rule).Next(getTime("Sun Jul 1 00:00 2012")); !actual.IsZero() {
+			t.Errorf("rule %d: expected the zero time, got %v", rule, actual)
+		}
+	}
+}
+
+func TestBusinessDay(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday(getTime("Wed Jul 4 00:00 2012"), "Independence Day")
+
+	tests := []struct {
+		n        int
+		time     string
+		expected string
+	}{
+		{1, "Sun Jul 1 00:00 2012", "Mon Jul 2 00:00 2012"},
+		{3, "Sun Jul 1 00:00 2012", "Thu Jul 5 00:00 2012"},
+		{3, "Thu Jul 5 00:00 2012", "Fri Aug 3 00:00 2012"},
+		{-1, "Sun Jul 1 00:00 2012", "Tue Jul 31 00:00 2012"},
+		{-1, "Tue Jul 31 00:00 2012", "Fri Aug 31 00:00 2012"},
+		{-23, "Sun Jul 1 00:00 2012", "Wed Aug 1 00:00 2012"},
+		{25, "Sun Jul 1 00:00 2012", ""},
+	}
+	for _, c := range tests {
+		actual := BusinessDay(c.n, cal).Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%d, %v: expected %v, got %v", c.n, c.time, expected, actual)
+		}
+	}
+}
+
+func TestParseBusinessDay(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday(getTime("Wed Jul 4 00:00 2012"), "Independence Day")
+	parser := standardParser.WithCalendar(cal)
+
+	tests := []struct {
+		spec     string
+		time     string
+		expected string
+		str      string
+	}{
+		{"@businessday 3 09:30", "Sun Jul 1 00:00 2012", "Thu Jul 5 09:30 2012", "@businessday 3 09:30"},
+		{"@businessday -1", "Sun Jul 1 00:00 2012", "Tue Jul 31 00:00 2012", "@businessday -1"},
+		{"CRON_TZ=UTC @businessday 1 18:00", "TZ=UTC 2012-07-01T00:00:00-0000", "TZ=UTC 2012-07-02T18:00:00-0000", "CRON_TZ=UTC @businessday 1 18:00"},
+	}
+	for _, c := range tests {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.spec, err)
+			continue
+		}
+		if actual, expected := sched.Next(getTime(c.time)), getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%s => expected %v, got %v", c.spec, expected, actual)
+		}
+		if actual := sched.(BusinessDaySchedule).String(); actual != c.str {
+			t.Errorf("%s => expected %q, got %q", c.spec, c.str, actual)
+		}
+	}
+
+	// Without a calendar only weekends are skipped.
+	sched, _ := standardParser.Parse("@businessday 3")
+	if actual, expected := sched.Next(getTime("Sun Jul 1 00:00 2012")), getTime("Wed Jul 4 00:00 2012"); !actual.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	errs := []struct {
+		spec   string
+		offset int
+		reason ParseErrorReason
+	}{
+		{"@businessday 0", 13, ReasonOutOfRange},
+		{"@businessday x", 13, ReasonOutOfRange},
+		{"@businessday 1 25:00", 15, ReasonSyntax},
+		{"@businessday 1 09:00 x", 0, ReasonFieldCount},
+		{"@businessday", 0, ReasonUnknownDescriptor},
+	}
+	for _, c := range errs {
+		_, err := parser.Parse(c.spec)
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Offset != c.offset || perr.Reason != c.reason {
+			t.Errorf("%s => expected %s error at %d, got %v", c.spec, c.reason, c.offset, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package cron
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Calendar是一组非工作日：每周的休息日（默认为周六和周日）以及节假日。
+// 日期按激活时间所在时区的日历日判断。
+// Calendar可以被多个时间表共享，但在使用过程中不应再修改。
+//
+// nil的*Calendar同样可用，它只把周六和周日视为非工作日。
+type Calendar struct {
+	weekend  [7]bool
+	holidays map[calendarDate]string
+}
+
+// calendarDate是不带时区的日历日。
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func dateOf(t time.Time) calendarDate {
+	y, m, d := t.Date()
+	return calendarDate{y, m, d}
+}
+
+// NewCalendar返回一个以周六和周日为休息日、没有节假日的Calendar。
+func NewCalendar() *Calendar {
+	c := &Calendar{}
+	c.SetWeekend(time.Saturday, time.Sunday)
+	return c
+}
+
+// SetWeekend将每周的休息日设置为给定的几天；不给出参数表示没有休息日。
+func (c *Calendar) SetWeekend(days ...time.Weekday) {
+	c.weekend = [7]bool{}
+	for _, day := range days {
+		c.weekend[day] = true
+	}
+}
+
+// AddHoliday将date所在的日历日添加为名为name的节假日。
+func (c *Calendar) AddHoliday(date time.Time, name string) {
+	if c.holidays == nil {
+		c.holidays = make(map[calendarDate]string)
+	}
+	c.holidays[dateOf(date)] = name
+}
+
+// Holiday返回t所在的日历日对应的节假日名称，以及该日是否为节假日。
+func (c *Calendar) Holiday(t time.Time) (name string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok = c.holidays[dateOf(t)]
+	return name, ok
+}
+
+// IsBusinessDay报告t所在的日历日是否为工作日，即既不是休息日也不是节假日。
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c == nil {
+		return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+	}
+	if c.weekend[t.Weekday()] {
+		return false
+	}
+	_, holiday := c.holidays[dateOf(t)]
+	return !holiday
+}
+
+// LoadCalendar从文件中读取节假日，返回以周六和周日为休息日的Calendar。
+// 扩展名为".ics"或".ical"的文件按iCalendar格式读取（见ReadICal），其他文件按CSV格式读取（见ReadCSV）。
+func LoadCalendar(filename string) (*Calendar, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := NewCalendar()
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics", ".ical":
+		err = c.ReadICal(f)
+	default:
+		err = c.ReadCSV(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return c, nil
+}
+
+// ReadCSV从r中读取节假日并添加到Calendar中。每行的格式为：
+//   YYYY-MM-DD [ "," name ]
+// 以"#"开头的行会被忽略，第一列为"date"的首行被视为标题。
+func (c *Calendar) ReadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		value := strings.TrimSpace(record[0])
+		if first && strings.EqualFold(value, "date") {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return fmt.Errorf("invalid holiday date %q: %v", value, err)
+		}
+		var name string
+		if len(record) > 1 {
+			name = strings.TrimSpace(record[1])
+		}
+		c.AddHoliday(date, name)
+	}
+}
+
+// ReadICal从r中读取iCalendar（RFC 5545）格式的节假日并添加到Calendar中。
+// 每个VEVENT的DTSTART（直到DTEND之前，如果有的话）所在的日期都被视为节假日，
+// SUMMARY为节假日的名称。重复规则（RRULE）会被忽略。
+func (c *Calendar) ReadICal(r io.Reader) error {
+	lines, numbers, err := unfoldICal(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		inEvent    bool
+		start, end time.Time
+		name       string
+	)
+	for i, line := range lines {
+		lineno := numbers[i]
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		property, value := line[:colon], line[colon+1:]
+		if semi := strings.Index(property, ";"); semi >= 0 {
+			property = property[:semi]
+		}
+
+		switch strings.ToUpper(property) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent, start, end, name = true, time.Time{}, time.Time{}, ""
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return fmt.Errorf("line %d: event without DTSTART", lineno)
+			}
+			c.AddHoliday(start, name)
+			for d := start.AddDate(0, 0, 1); d.Before(end); d = d.AddDate(0, 0, 1) {
+				c.AddHoliday(d, name)
+			}
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			if len(value) < 8 {
+				return fmt.Errorf("line %d: invalid date %q", lineno, value)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid date %q: %v", lineno, value, err)
+			}
+			if strings.EqualFold(property, "DTSTART") {
+				start = date
+			} else {
+				end = date
+			}
+		case "SUMMARY":
+			if inEvent {
+				name = unescapeICal(value)
+			}
+		}
+	}
+	return nil
+}
+
+// unfoldICal读取r中的所有内容行，并将以空白开头的续行合并到上一行。
+// numbers是每个内容行在r中的起始行号。
+func unfoldICal(r io.Reader) (lines []string, numbers []int, err error) {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+		numbers = append(numbers, n)
+	}
+	return lines, numbers, scanner.Err()
+}
+
+var icalUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\;`, `;`, `\n`, "\n", `\N`, "\n")
+
+func unescapeICal(value string) string {
+	return icalUnescaper.Replace(value)
+}
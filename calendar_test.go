@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	cal := NewCalendar()
+	cal.AddHoliday(getTime("Wed Jul 4 00:00 2012"), "Independence Day")
+
+	tests := []struct {
+		time     string
+		business bool
+	}{
+		{"Tue Jul 3 23:59 2012", true},
+		{"Wed Jul 4 00:00 2012", false},
+		{"Wed Jul 4 23:59 2012", false},
+		{"Thu Jul 5 00:00 2012", true},
+		{"Sat Jul 7 12:00 2012", false},
+		{"Sun Jul 8 12:00 2012", false},
+		{"TZ=UTC 2012-07-04T03:00:00-0000", false},
+		{"TZ=UTC 2012-07-05T03:00:00-0000", true},
+	}
+	for _, test := range tests {
+		if actual := cal.IsBusinessDay(getTime(test.time)); actual != test.business {
+			t.Errorf("%s => expected %v, got %v", test.time, test.business, actual)
+		}
+	}
+
+	if name, ok := cal.Holiday(getTime("Wed Jul 4 12:00 2012")); !ok || name != "Independence Day" {
+		t.Errorf("expected Independence Day, got %q, %v", name, ok)
+	}
+
+	cal.SetWeekend(time.Friday)
+	if !cal.IsBusinessDay(getTime("Sat Jul 7 12:00 2012")) || cal.IsBusinessDay(getTime("Fri Jul 6 12:00 2012")) {
+		t.Error("expected only Fridays to be non-business days")
+	}
+
+	var none *Calendar
+	if none.IsBusinessDay(getTime("Sat Jul 7 12:00 2012")) || !none.IsBusinessDay(getTime("Wed Jul 4 12:00 2012")) {
+		t.Error("expected a nil calendar to observe weekends only")
+	}
+}
+
+func TestCalendarReadCSV(t *testing.T) {
+	const data = `date,name
+# US federal holidays
+2012-07-04,Independence Day
+2012-09-03, "Labor Day, observed"
+2012-12-25
+`
+	cal := NewCalendar()
+	if err := cal.ReadCSV(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Wed Jul 4 00:00 2012":  "Independence Day",
+		"Mon Sep 3 00:00 2012":  "Labor Day, observed",
+		"Tue Dec 25 00:00 2012": "",
+	}
+	for value, name := range expected {
+		if actual, ok := cal.Holiday(getTime(value)); !ok || actual != name {
+			t.Errorf("%s => expected %q, got %q (%v)", value, name, actual, ok)
+		}
+	}
+
+	if err := cal.ReadCSV(strings.NewReader("2012-13-01,Bad\n")); err == nil || !strings.Contains(err.Error(), "invalid holiday date") {
+		t.Errorf("expected an error, got %v", err)
+	}
+}
+
+func TestCalendarReadICal(t *testing.T) {
+	const data = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20121224\r\n" +
+		"DTEND;VALUE=DATE:20121227\r\n" +
+		"SUMMARY:Christmas\\, and\r\n" +
+		"  Boxing Day\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20120704T000000Z\r\n" +
+		"SUMMARY:Independence Day\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal := NewCalendar()
+	if err := cal.ReadICal(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"Mon Dec 24 00:00 2012": "Christmas, and Boxing Day",
+		"Tue Dec 25 00:00 2012": "Christmas, and Boxing Day",
+		"Wed Dec 26 00:00 2012": "Christmas, and Boxing Day",
+		"Wed Jul 4 00:00 2012":  "Independence Day",
+	}
+	for value, name := range expected {
+		if actual, ok := cal.Holiday(getTime(value)); !ok || actual != name {
+			t.Errorf("%s => expected %q, got %q (%v)", value, name, actual, ok)
+		}
+	}
+	if _, ok := cal.Holiday(getTime("Thu Dec 27 00:00 2012")); ok {
+		t.Error("DTEND should be exclusive")
+	}
+
+	err := cal.ReadICal(strings.NewReader("BEGIN:VEVENT\nSUMMARY:Nothing\nEND:VEVENT\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected an error on line 3, got %v", err)
+	}
+}
+
+func TestLoadCalendar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"holidays.csv": "2012-07-04,Independence Day\n",
+		"holidays.ics": "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20120704\nEND:VEVENT\n",
+	}
+	for name, data := range files {
+		filename := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cal, err := LoadCalendar(filename)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", name, err)
+			continue
+		}
+		if cal.IsBusinessDay(getTime("Wed Jul 4 12:00 2012")) {
+			t.Errorf("%s => expected Jul 4 to be a holiday", name)
+		}
+	}
+
+	if _, err := LoadCalendar(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Error("expected an error, got none")
+	}
+}
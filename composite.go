@@ -0,0 +1,134 @@
+package cron
+
+import "time"
+
+// compositeYears是IntersectSchedule和ExceptSchedule向后查找的年数，与SpecSchedule相同。
+// 在此范围内（或MaxActivations次尝试内）找不到满足条件的时间时，Next返回时间的零值。
+const compositeYears = 5
+
+// UnionSchedule在任意一个子时间表激活时激活。
+type UnionSchedule struct {
+	Schedules []Schedule
+}
+
+// Union返回在任意一个给定时间表激活时激活的时间表，例如同时按两个spec运行的作业。
+func Union(schedules ...Schedule) UnionSchedule {
+	return UnionSchedule{schedules}
+}
+
+// Next返回所有子时间表的下一个激活时间中最早的一个。
+// 如果所有子时间表都不再激活，则返回时间的零值。
+func (s UnionSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, schedule := range s.Schedules {
+		n := schedule.Next(t)
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// IntersectSchedule只在所有子时间表同时激活时激活。
+type IntersectSchedule struct {
+	Schedules []Schedule
+}
+
+// Intersect返回只在所有给定时间表同时激活时激活的时间表，
+// 例如"每15分钟一次"与"仅在工作日的9-17点"。
+func Intersect(schedules ...Schedule) IntersectSchedule {
+	return IntersectSchedule{schedules}
+}
+
+// Next返回晚于t、且所有子时间表都会激活的最早时间。
+// 它不断把候选时间推进到各个子时间表不早于它的下一个激活时间，直到所有子时间表一致。
+func (s IntersectSchedule) Next(t time.Time) time.Time {
+	if len(s.Schedules) == 0 {
+		return time.Time{}
+	}
+
+	var (
+		limit     = t.AddDate(compositeYears, 0, 0)
+		candidate = s.Schedules[0].Next(t)
+	)
+	for attempts := 0; attempts < MaxActivations; attempts++ {
+		if candidate.IsZero() || candidate.After(limit) {
+			return time.Time{}
+		}
+		agreed := true
+		for _, schedule := range s.Schedules {
+			n := nextAtOrAfter(schedule, candidate)
+			if !n.Equal(candidate) {
+				candidate, agreed = n, false
+				break
+			}
+		}
+		if agreed {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+// ExceptSchedule在Base激活、而Blackout没有在同一时刻激活时激活。
+// 只有与Blackout的激活时间完全相同的激活才会被跳过，Blackout本身并不表示一段时间。
+type ExceptSchedule struct {
+	Base, Blackout Schedule
+}
+
+// Except返回跳过blackout所有激活时间的base。
+// blackout为"0 2 * * *"时只跳过02:00这一次激活；要排除一段时间，请使用ExceptFunc。
+func Except(base, blackout Schedule) ExceptSchedule {
+	return ExceptSchedule{base, blackout}
+}
+
+// Next返回Base晚于t、且不是Blackout激活时间的下一个激活时间。
+func (s ExceptSchedule) Next(t time.Time) time.Time {
+	return nextExcept(s.Base, t, func(next time.Time) bool {
+		return nextAtOrAfter(s.Blackout, next).Equal(next)
+	})
+}
+
+// ExceptFuncSchedule在Base激活、而Skip对该激活时间返回false时激活。
+type ExceptFuncSchedule struct {
+	Base Schedule
+	Skip func(time.Time) bool
+}
+
+// ExceptFunc返回跳过skip返回true的所有激活时间的base，用于排除一段时间，
+// 例如"每5分钟一次，但02:00-03:00除外"：
+//
+//  base, _ := cron.ParseStandard("*/5 * * * *")
+//  sched := cron.ExceptFunc(base, func(t time.Time) bool { return t.Hour() == 2 })
+//
+// skip收到的是base返回的时间，需要按其他时区判断时先用In转换。与Except不同，
+// base的激活时间不需要与其他时间表对齐，因此同样适用于"@every 5m"。
+//
+func ExceptFunc(base Schedule, skip func(time.Time) bool) ExceptFuncSchedule {
+	return ExceptFuncSchedule{base, skip}
+}
+
+// Next返回Base晚于t、且Skip返回false的下一个激活时间。
+func (s ExceptFuncSchedule) Next(t time.Time) time.Time {
+	return nextExcept(s.Base, t, s.Skip)
+}
+
+// nextExcept返回base晚于t、且skip返回false的下一个激活时间。
+func nextExcept(base Schedule, t time.Time, skip func(time.Time) bool) time.Time {
+	limit := t.AddDate(compositeYears, 0, 0)
+	for attempts := 0; attempts < MaxActivations; attempts++ {
+		t = base.Next(t)
+		if t.IsZero() || t.After(limit) {
+			return time.Time{}
+		}
+		if !skip(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// nextAtOrAfter返回schedule不早于t的下一个激活时间。
+func nextAtOrAfter(schedule Schedule, t time.Time) time.Time {
+	return schedule.Next(t.Add(-time.Nanosecond))
+}
@@ -0,0 +1,138 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnion(t *testing.T) {
+	a, _ := ParseStandard("0 9 * * mon-fri")
+	b, _ := ParseStandard("30 11 * * sat,sun")
+	yearParser := NewParser(Minute | Hour | Dom | Month | Dow | Year)
+	expired, _ := yearParser.Parse("0 0 * * * 2011")
+
+	tests := []struct {
+		schedule Schedule
+		time     string
+		expected string
+	}{
+		{Union(a, b), "Fri Jul 13 10:00 2012", "Sat Jul 14 11:30 2012"},
+		{Union(a, b), "Sun Jul 15 11:30 2012", "Mon Jul 16 09:00 2012"},
+		{Union(b, a), "Sun Jul 15 11:30 2012", "Mon Jul 16 09:00 2012"},
+		{Union(a, expired), "Sun Jul 15 11:30 2012", "Mon Jul 16 09:00 2012"},
+		{Union(expired), "Sun Jul 15 11:30 2012", ""},
+		{Union(), "Sun Jul 15 11:30 2012", ""},
+	}
+	for _, c := range tests {
+		actual := c.schedule.Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", c.time, expected, actual)
+		}
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	quarterly, _ := ParseStandard("*/15 * * * *")
+	officeHours, _ := ParseStandard("* 9-17 * * mon-fri")
+	firstOfMonth, _ := ParseStandard("0 0 1 * *")
+	monday, _ := ParseStandard("0 0 * * mon")
+	tuesday, _ := ParseStandard("0 0 * * tue")
+
+	tests := []struct {
+		schedule Schedule
+		time     string
+		expected string
+	}{
+		{Intersect(quarterly, officeHours), "Mon Jul 9 10:05 2012", "Mon Jul 9 10:15 2012"},
+		{Intersect(quarterly, officeHours), "Fri Jul 13 17:45 2012", "Mon Jul 16 09:00 2012"},
+		{Intersect(officeHours, quarterly), "Fri Jul 13 17:45 2012", "Mon Jul 16 09:00 2012"},
+
+		// The first Monday that is also the first of the month.
+		{Intersect(firstOfMonth, monday), "Mon Jul 9 10:05 2012", "Mon Oct 1 00:00 2012"},
+
+		// Schedules that never coincide.
+		{Intersect(monday, tuesday), "Mon Jul 9 10:05 2012", ""},
+		{Intersect(quarterly, Every(7*time.Minute)), "Mon Jul 9 10:05 2012", ""},
+		{Intersect(), "Mon Jul 9 10:05 2012", ""},
+	}
+	for _, c := range tests {
+		actual := c.schedule.Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", c.time, expected, actual)
+		}
+	}
+}
+
+func TestExcept(t *testing.T) {
+	base, _ := ParseStandard("*/5 * * * *")
+	blackout, _ := ParseStandard("* 2 * * *")
+	always, _ := ParseStandard("* * * * *")
+	officeHours, _ := ParseStandard("* 9-17 * * mon-fri")
+	twoOClock, _ := ParseStandard("0 2 * * *")
+	everySecond, _ := secondParser.Parse("* * 2 * * *")
+	every := Every(5 * time.Minute)
+
+	tests := []struct {
+		schedule Schedule
+		time     string
+		expected string
+	}{
+		{Except(base, blackout), "Mon Jul 9 01:50 2012", "Mon Jul 9 01:55 2012"},
+		{Except(base, blackout), "Mon Jul 9 01:55 2012", "Mon Jul 9 03:00 2012"},
+		{Except(base, blackout), "Mon Jul 9 02:20 2012", "Mon Jul 9 03:00 2012"},
+		{Except(base, blackout), "Mon Jul 9 03:00 2012", "Mon Jul 9 03:05 2012"},
+
+		// Blackout windows may also be composites.
+		{Except(base, Union(blackout, officeHours)), "Mon Jul 9 08:55 2012", "Mon Jul 9 18:00 2012"},
+
+		// Nothing is left once everything is blacked out.
+		{Except(base, always), "Mon Jul 9 03:00 2012", ""},
+
+		// Only activations at the exact same instant are skipped.
+		{Except(base, twoOClock), "Mon Jul 9 01:55 2012", "Mon Jul 9 02:05 2012"},
+		{Except(every, blackout), "Mon Jul 9 01:58:30 2012", "Mon Jul 9 02:03:30 2012"},
+		{Except(every, everySecond), "Mon Jul 9 01:58:30 2012", "Mon Jul 9 03:03:30 2012"},
+	}
+	for _, c := range tests {
+		actual := c.schedule.Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", c.time, expected, actual)
+		}
+	}
+}
+
+func TestExceptFunc(t *testing.T) {
+	base, _ := ParseStandard("*/5 * * * *")
+	every := Every(5 * time.Minute)
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	twoOClock := func(t time.Time) bool { return t.Hour() == 2 }
+	twoOClockTokyo := func(t time.Time) bool { return t.In(tokyo).Hour() == 2 }
+	always := func(time.Time) bool { return true }
+
+	tests := []struct {
+		schedule Schedule
+		time     string
+		expected string
+	}{
+		// Every 5 minutes except between 02:00 and 03:00.
+		{ExceptFunc(base, twoOClock), "Mon Jul 9 01:50 2012", "Mon Jul 9 01:55 2012"},
+		{ExceptFunc(base, twoOClock), "Mon Jul 9 01:55 2012", "Mon Jul 9 03:00 2012"},
+		{ExceptFunc(base, twoOClock), "Mon Jul 9 02:20 2012", "Mon Jul 9 03:00 2012"},
+		{ExceptFunc(base, twoOClock), "Mon Jul 9 03:00 2012", "Mon Jul 9 03:05 2012"},
+
+		// Bases not aligned to whole minutes are skipped all the same.
+		{ExceptFunc(every, twoOClock), "Mon Jul 9 01:58:30 2012", "Mon Jul 9 03:03:30 2012"},
+
+		// Windows in another time zone convert the time first.
+		{ExceptFunc(base, twoOClockTokyo), "2012-07-08T16:55:00-0000", "2012-07-08T18:00:00-0000"},
+
+		// Nothing is left once everything is skipped.
+		{ExceptFunc(base, always), "Mon Jul 9 03:00 2012", ""},
+	}
+	for _, c := range tests {
+		actual := c.schedule.Next(getTime(c.time))
+		if expected := getTime(c.expected); !actual.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", c.time, expected, actual)
+		}
+	}
+}
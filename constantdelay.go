@@ -1,6 +1,9 @@
 package cron
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // ConstantDelaySchedule表示一个简单的循环工作周期，例如“每5分钟”。
 // 它不支持比每秒更频繁的作业。
@@ -25,3 +28,177 @@ func Every(duration time.Duration) ConstantDelaySchedule {
 func (schedule ConstantDelaySchedule) Next(t time.Time) time.Time {
 	return t.Add(schedule.Delay - time.Duration(t.Nanosecond())*time.Nanosecond)
 }
+
+// Prev返回上一次应运行的时间，与Next相对应，即给定时间向前一个延迟。
+// 此操作将四舍五入，以使上一个激活时间为秒。
+func (schedule ConstantDelaySchedule) Prev(t time.Time) time.Time {
+	return t.Add(-schedule.Delay - time.Duration(t.Nanosecond())*time.Nanosecond)
+}
+
+// String返回该时间表的规范spec，形式为"@every <duration>"。
+func (schedule ConstantDelaySchedule) String() string {
+	return "@every " + schedule.Delay.String()
+}
+
+// MarshalText实现了encoding.TextMarshaler，返回String的结果。
+func (schedule ConstantDelaySchedule) MarshalText() ([]byte, error) {
+	return []byte(schedule.String()), nil
+}
+
+// UnmarshalText实现了encoding.TextUnmarshaler，解析"@every <duration>"形式的spec。
+func (schedule *ConstantDelaySchedule) UnmarshalText(text []byte) error {
+	parsed, err := canonicalParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	delay, ok := parsed.(ConstantDelaySchedule)
+	if !ok {
+		return fmt.Errorf("not a constant delay schedule: %s", text)
+	}
+	*schedule = delay
+	return nil
+}
+
+// PreciseDelaySchedule与ConstantDelaySchedule一样每隔Delay激活一次，但不会舍入到秒，
+// 因此可以用于100ms这样的亚秒级间隔。
+type PreciseDelaySchedule struct {
+	Delay time.Duration
+}
+
+// EveryPrecisely返回每隔duration激活一次的时间表，duration精确到毫秒，最少为1毫秒。
+func EveryPrecisely(duration time.Duration) PreciseDelaySchedule {
+	if duration < time.Millisecond {
+		duration = time.Millisecond
+	}
+	return PreciseDelaySchedule{
+		Delay: duration - duration%time.Millisecond,
+	}
+}
+
+// Next返回t之后一个间隔的时间。
+func (schedule PreciseDelaySchedule) Next(t time.Time) time.Time {
+	return t.Add(schedule.Delay)
+}
+
+// Prev返回t之前一个间隔的时间。
+func (schedule PreciseDelaySchedule) Prev(t time.Time) time.Time {
+	return t.Add(-schedule.Delay)
+}
+
+// String返回该时间表的规范spec，形式为"@every <duration>"。
+func (schedule PreciseDelaySchedule) String() string {
+	return "@every " + schedule.Delay.String()
+}
+
+// AnchoredDelaySchedule与ConstantDelaySchedule一样按固定的间隔激活，
+// 但激活时间落在以Anchor为起点的固定网格上，不受调用Next的时间（例如进程的启动时间）影响。
+type AnchoredDelaySchedule struct {
+	Delay  time.Duration
+	Anchor time.Time
+
+	// Daily为true时只使用Anchor的时刻（按Anchor的时区）：网格每天从该时刻重新开始，
+	// 当天的最后一次激活之后直接到次日的该时刻。此时Delay不应超过24小时。
+	Daily bool
+}
+
+// EveryFrom返回激活时间为anchor + k*duration（k为任意整数）的时间表。
+// 与Every一样，duration会舍入到秒；"@every"描述符则与EveryPrecisely一样精确到毫秒。
+func EveryFrom(duration time.Duration, anchor time.Time) AnchoredDelaySchedule {
+	return AnchoredDelaySchedule{Delay: Every(duration).Delay, Anchor: anchor}
+}
+
+// Next返回网格上晚于t的下一个时间。
+func (schedule AnchoredDelaySchedule) Next(t time.Time) time.Time {
+	if schedule.Delay <= 0 {
+		return time.Time{}
+	}
+	if !schedule.Daily {
+		return gridAfter(schedule.Anchor, schedule.Delay, t)
+	}
+	start, end := schedule.day(t)
+	if next := gridAfter(start, schedule.Delay, t); next.Before(end) {
+		return next
+	}
+	return end
+}
+
+// Prev返回网格上不晚于t的最近一个时间。
+func (schedule AnchoredDelaySchedule) Prev(t time.Time) time.Time {
+	if schedule.Delay <= 0 {
+		return time.Time{}
+	}
+	if !schedule.Daily {
+		return gridAtOrBefore(schedule.Anchor, schedule.Delay, t)
+	}
+	start, _ := schedule.day(t)
+	return gridAtOrBefore(start, schedule.Delay, t)
+}
+
+// day返回t所在的那一天的网格：从不晚于t的最近一次Anchor时刻开始，到次日的该时刻结束。
+func (schedule AnchoredDelaySchedule) day(t time.Time) (start, end time.Time) {
+	var (
+		loc          = schedule.Anchor.Location()
+		hour, min, s = schedule.Anchor.Clock()
+		nsec         = schedule.Anchor.Nanosecond()
+		y, m, d      = t.In(loc).Date()
+	)
+	start = time.Date(y, m, d, hour, min, s, nsec, loc)
+	if start.After(t) {
+		start = time.Date(y, m, d-1, hour, min, s, nsec, loc)
+	}
+	end = time.Date(start.Year(), start.Month(), start.Day()+1, hour, min, s, nsec, loc)
+	return start, end
+}
+
+// gridAfter返回start + k*delay（k为任意整数）中晚于t的最早时间。
+func gridAfter(start time.Time, delay time.Duration, t time.Time) time.Time {
+	d := t.Sub(start)
+	k := d / delay
+	if d < 0 && d%delay != 0 {
+		k--
+	}
+	return start.Add((k + 1) * delay)
+}
+
+// gridAtOrBefore返回start + k*delay（k为任意整数）中不晚于t的最晚时间，
+// 即晚于t-delay的最早时间。
+func gridAtOrBefore(start time.Time, delay time.Duration, t time.Time) time.Time {
+	return gridAfter(start, delay, t.Add(-delay))
+}
+
+// String返回该时间表的规范spec，例如"@every 1h0m0s from 00:15"或
+// "@every 1h30m0s anchored 2026-01-01T00:00:00Z"。
+func (schedule AnchoredDelaySchedule) String() string {
+	spec := "@every " + schedule.Delay.String()
+	if !schedule.Daily {
+		return spec + " anchored " + schedule.Anchor.Format(time.RFC3339Nano)
+	}
+	layout := "15:04"
+	if schedule.Anchor.Second() != 0 {
+		layout = "15:04:05"
+	}
+	spec += " from " + schedule.Anchor.Format(layout)
+	if loc := schedule.Anchor.Location(); loc != time.Local {
+		spec = "CRON_TZ=" + loc.String() + " " + spec
+	}
+	return spec
+}
+
+// MarshalText实现了encoding.TextMarshaler，返回String的结果。
+func (schedule AnchoredDelaySchedule) MarshalText() ([]byte, error) {
+	return []byte(schedule.String()), nil
+}
+
+// UnmarshalText实现了encoding.TextUnmarshaler，解析String返回的spec。
+func (schedule *AnchoredDelaySchedule) UnmarshalText(text []byte) error {
+	parsed, err := canonicalParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	anchored, ok := parsed.(AnchoredDelaySchedule)
+	if !ok {
+		return fmt.Errorf("not an anchored delay schedule: %s", text)
+	}
+	*schedule = anchored
+	return nil
+}
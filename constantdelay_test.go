@@ -52,3 +52,174 @@ func TestConstantDelayNext(t *testing.T) {
 		}
 	}
 }
+
+func TestConstantDelayPrev(t *testing.T) {
+	tests := []struct {
+		time     string
+		delay    time.Duration
+		expected string
+	}{
+		{"Mon Jul 9 15:00 2012", 15 * time.Minute, "Mon Jul 9 14:45 2012"},
+		{"Tue Jul 10 00:20 2012", 35 * time.Minute, "Mon Jul 9 23:45 2012"},
+		{"Tue Jan 1 00:00:00 2013", 15 * time.Second, "Mon Dec 31 23:59:45 2012"},
+
+		// Round to nearest second when calculating the previous time.
+		{"Mon Jul 9 15:00:00.005 2012", 15 * time.Minute, "Mon Jul 9 14:45 2012"},
+	}
+
+	for _, c := range tests {
+		actual := Every(c.delay).Prev(getTime(c.time))
+		expected := getTime(c.expected)
+		if actual != expected {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.delay, expected, actual)
+		}
+	}
+}
+
+func TestAnchoredDelay(t *testing.T) {
+	tests := []struct {
+		spec           string
+		time           string
+		next, previous string
+	}{
+		{"@every 1h from 00:15", "Mon Jul 9 14:45 2012", "Mon Jul 9 15:15 2012", "Mon Jul 9 14:15 2012"},
+		{"@every 1h from 00:15", "Mon Jul 9 15:15 2012", "Mon Jul 9 16:15 2012", "Mon Jul 9 15:15 2012"},
+		{"@every 1h from 00:15", "Mon Jul 9 15:14:59 2012", "Mon Jul 9 15:15 2012", "Mon Jul 9 14:15 2012"},
+		{"@every 90m from 00:15", "Mon Jul 9 14:45 2012", "Mon Jul 9 15:15 2012", "Mon Jul 9 13:45 2012"},
+		{"@every 90m from 00:15", "Mon Jul 9 23:00 2012", "Tue Jul 10 00:15 2012", "Mon Jul 9 22:45 2012"},
+
+		// The grid restarts every day at the anchor.
+		{"@every 7h from 09:00", "Mon Jul 9 23:30 2012", "Tue Jul 10 06:00 2012", "Mon Jul 9 23:00 2012"},
+		{"@every 7h from 09:00", "Tue Jul 10 07:00 2012", "Tue Jul 10 09:00 2012", "Tue Jul 10 06:00 2012"},
+		{"@every 7h from 09:00", "Tue Jul 10 09:00 2012", "Tue Jul 10 16:00 2012", "Tue Jul 10 09:00 2012"},
+		{"@every 7h from 09:00", "Tue Jul 10 06:00 2012", "Tue Jul 10 09:00 2012", "Tue Jul 10 06:00 2012"},
+		{"@every 7h from 09:00:30", "Tue Jul 10 08:00 2012", "Tue Jul 10 09:00:30 2012", "Tue Jul 10 06:00:30 2012"},
+		{"CRON_TZ=Asia/Tokyo @every 6h from 03:00", "2012-07-09T00:00:00-0000",
+			"TZ=Asia/Tokyo 2012-07-09T15:00:00+0900", "TZ=Asia/Tokyo 2012-07-09T09:00:00+0900"},
+		{"CRON_TZ=America/New_York @every 12h from 06:00", "TZ=America/New_York 2012-03-10T19:00:00-0500",
+			"TZ=America/New_York 2012-03-11T06:00:00-0400", "TZ=America/New_York 2012-03-10T18:00:00-0500"},
+
+		// Fixed anchors extend in both directions.
+		{"@every 90m anchored 2026-01-01T00:00Z", "2026-01-01T02:00:00-0000", "2026-01-01T03:00:00-0000", "2026-01-01T01:30:00-0000"},
+		{"@every 90m anchored 2026-01-01T00:00Z", "2025-12-31T23:00:00-0000", "2026-01-01T00:00:00-0000", "2025-12-31T22:30:00-0000"},
+		{"@every 90m anchored 2026-01-01T00:00Z", "2026-01-01T00:00:00-0000", "2026-01-01T01:30:00-0000", "2026-01-01T00:00:00-0000"},
+		{"@every 90m anchored 2026-01-01T00:00Z", "2026-01-01T04:30:00-0000", "2026-01-01T06:00:00-0000", "2026-01-01T04:30:00-0000"},
+
+		// Sub-second intervals are not rounded.
+		{"@every 1.5s anchored 2026-01-01T00:00Z", "2026-01-01T00:00:02-0000", "2026-01-01T00:00:03-0000", "2026-01-01T00:00:01.5-0000"},
+		{"@every 250ms from 09:00", "Tue Jul 10 09:00:01 2012", "Tue Jul 10 09:00:01.25 2012", "Tue Jul 10 09:00:01 2012"},
+		{"CRON_TZ=UTC @every 24h anchored 2026-01-01T09:00", "2026-03-01T10:00:00-0000", "2026-03-02T09:00:00-0000", "2026-03-01T09:00:00-0000"},
+	}
+	for _, test := range tests {
+		schedule, err := standardParser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		anchored := schedule.(AnchoredDelaySchedule)
+		if actual, expected := anchored.Next(getTime(test.time)), getTime(test.next); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": next (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+		if actual, expected := anchored.Prev(getTime(test.time)), getTime(test.previous); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": prev (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+	}
+}
+
+func TestAnchoredDelayString(t *testing.T) {
+	tests := []struct {
+		spec, expected string
+	}{
+		{"@every 1h from 00:15", "@every 1h0m0s from 00:15"},
+		{"@every 1h from 00:15:30", "@every 1h0m0s from 00:15:30"},
+		{"CRON_TZ=Asia/Tokyo @every 6h from 03:00", "CRON_TZ=Asia/Tokyo @every 6h0m0s from 03:00"},
+		{"@every 90m anchored 2026-01-01T00:00Z", "@every 1h30m0s anchored 2026-01-01T00:00:00Z"},
+	}
+	for _, test := range tests {
+		schedule, err := standardParser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		anchored := schedule.(AnchoredDelaySchedule)
+		if actual := anchored.String(); actual != test.expected {
+			t.Errorf("%s => expected %q, got %q", test.spec, test.expected, actual)
+		}
+
+		var parsed AnchoredDelaySchedule
+		if err := parsed.UnmarshalText([]byte(anchored.String())); err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+		} else if parsed.Delay != anchored.Delay || !parsed.Anchor.Equal(anchored.Anchor) || parsed.Daily != anchored.Daily {
+			t.Errorf("%s => round trip gave %v", test.spec, parsed)
+		}
+	}
+}
+
+func TestAnchoredDelayErrors(t *testing.T) {
+	tests := []struct {
+		spec   string
+		reason ParseErrorReason
+		token  string
+		offset int
+	}{
+		{"@every 1h from", ReasonFieldCount, "@every 1h from", 0},
+		{"@every 1h from 25:00", ReasonSyntax, "25:00", 15},
+		{"@every 25h from 00:00", ReasonOutOfRange, "25h", 7},
+		{"@every 1h since 00:00", ReasonSyntax, "since", 10},
+		{"@every 1h anchored tomorrow", ReasonSyntax, "tomorrow", 19},
+		{"CRON_TZ=UTC @every 1h from 1:2:3:4", ReasonSyntax, "1:2:3:4", 27},
+	}
+	for _, test := range tests {
+		_, err := standardParser.Parse(test.spec)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q => expected a *ParseError, got %v", test.spec, err)
+			continue
+		}
+		if perr.Reason != test.reason || perr.Token != test.token || perr.Offset != test.offset {
+			t.Errorf("%q => expected %v %q at %d, got %v %q at %d (%v)", test.spec,
+				test.reason, test.token, test.offset, perr.Reason, perr.Token, perr.Offset, perr)
+		}
+	}
+}
+
+func TestPreciseDelay(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected time.Duration
+	}{
+		{"@every 100ms", 100 * time.Millisecond},
+		{"@every 250ms", 250 * time.Millisecond},
+		{"@every 1.5s", 1500 * time.Millisecond},
+	}
+	start := time.Date(2012, time.July, 9, 14, 45, 0, 123456789, time.Local)
+	for _, test := range tests {
+		schedule, err := standardParser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		precise, ok := schedule.(PreciseDelaySchedule)
+		if !ok || precise.Delay != test.expected {
+			t.Errorf("%s => expected every %v, got %#v", test.spec, test.expected, schedule)
+			continue
+		}
+		if actual := precise.Next(start); !actual.Equal(start.Add(test.expected)) {
+			t.Errorf("%s => expected %v, got %v", test.spec, start.Add(test.expected), actual)
+		}
+		if actual := precise.Prev(start); !actual.Equal(start.Add(-test.expected)) {
+			t.Errorf("%s => expected %v, got %v", test.spec, start.Add(-test.expected), actual)
+		}
+		if precise.String() != "@every "+test.expected.String() {
+			t.Errorf("%s => unexpected string %q", test.spec, precise.String())
+		}
+	}
+
+	// Whole seconds keep the rounding behaviour of Every.
+	if schedule, _ := standardParser.Parse("@every 2s"); schedule != Every(2*time.Second) {
+		t.Errorf("expected a constant delay, got %#v", schedule)
+	}
+	if d := EveryPrecisely(100*time.Microsecond + 5).Delay; d != time.Millisecond {
+		t.Errorf("expected a minimum of 1ms, got %v", d)
+	}
+}
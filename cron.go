@@ -42,6 +42,17 @@ type Schedule interface {
 	Next(time.Time) time.Time
 }
 
+// PrevSchedule是可选的接口，由能够计算上一次激活时间的Schedule实现。
+// 从调用Next的时间算起的时间表（例如Every）没有固定的激活时间，它们的Prev与Next相对应，
+// 返回给定时间之前一个间隔的时间。
+type PrevSchedule interface {
+	Schedule
+
+	// Prev返回不晚于给定时间的最近一次激活时间。
+	// 如果找不到满足时间表的时间，则返回时间的零值。
+	Prev(time.Time) time.Time
+}
+
 // EntryID 在一个Cron实例中指定一个条目
 type EntryID int
 
@@ -65,7 +76,14 @@ type Entry struct {
 	// Job是提交给cron的东西。
 	// 它被保留下来，以便以后需要使用的用户代码，
 	// 例如：通过Entries（）可以做到。
+	// 由AddJobCtx提交的作业在这里是一个适配器，提交的作业见JobWithContext。
 	Job Job
+
+	// JobWithContext是由AddJobCtx提交的作业，其他条目为nil。
+	JobWithContext JobWithContext
+
+	// started表示该条目是否已经在启动时激活过，用于RebootSchedule。
+	started bool
 }
 
 // 如果不是一个零值的条目, Valid 返回true
@@ -152,14 +170,23 @@ func (c *Cron) AddJob(spec string, cmd Job) (EntryID, error) {
 // 将作业添加到Cron中，以便按给定的时间表运行。
 // 该作业由配置的Chain包裹。
 func (c *Cron) Schedule(schedule Schedule, cmd Job) EntryID {
+	return c.schedule(schedule, cmd, nil)
+}
+
+// schedule实现了Schedule和ScheduleCtx，ctxCmd不为nil时cmd为它的适配器。
+func (c *Cron) schedule(schedule Schedule, cmd Job, ctxCmd JobWithContext) EntryID {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
 	c.nextID++
+	if ctxCmd != nil {
+		cmd = &contextJob{job: ctxCmd, id: c.nextID, cron: c, handoff: make(chan struct{}, 1)}
+	}
 	entry := &Entry{
-		ID:         c.nextID,
-		Schedule:   schedule,
-		WrappedJob: c.chain.Then(cmd),
-		Job:        cmd,
+		ID:             c.nextID,
+		Schedule:       schedule,
+		WrappedJob:     c.chain.Then(cmd),
+		Job:            cmd,
+		JobWithContext: ctxCmd,
 	}
 	if !c.running {
 		c.entries = append(c.entries, entry)
@@ -196,7 +223,7 @@ func (c *Cron) Entry(id EntryID) Entry {
 	return Entry{}
 }
 
-// 删除将来运行的条目。
+// 删除将来运行的条目。正在运行的JobWithContext的上下文会被取消。
 func (c *Cron) Remove(id EntryID) {
 	c.runningMu.Lock()
 	defer c.runningMu.Unlock()
@@ -234,10 +261,15 @@ func (c *Cron) Run() {
 func (c *Cron) run() {
 	c.logger.Info("start")
 
+	// The context of JobWithContext entries is cancelled when the scheduler stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Figure out the next activation times for each entry.
 	now := c.now()
 	for _, entry := range c.entries {
-		entry.Next = entry.Schedule.Next(now)
+		startContext(ctx, entry)
+		entry.Next = firstNext(entry, now)
 		c.logger.Info("schedule", "now", now, "entry", entry.ID, "next", entry.Next)
 	}
 
@@ -265,16 +297,17 @@ func (c *Cron) run() {
 					if e.Next.After(now) || e.Next.IsZero() {
 						break
 					}
-					c.startJob(e.WrappedJob)
+					c.startJob(e, e.Next)
 					e.Prev = e.Next
-					e.Next = e.Schedule.Next(now)
+					e.Next = nextAfter(e.Schedule, e.Prev, now)
 					c.logger.Info("run", "now", now, "entry", e.ID, "next", e.Next)
 				}
 
 			case newEntry := <-c.add:
 				timer.Stop()
 				now = c.now()
-				newEntry.Next = newEntry.Schedule.Next(now)
+				startContext(ctx, newEntry)
+				newEntry.Next = firstNext(newEntry, now)
 				c.entries = append(c.entries, newEntry)
 				c.logger.Info("added", "now", now, "entry", newEntry.ID, "next", newEntry.Next)
 
@@ -284,6 +317,7 @@ func (c *Cron) run() {
 
 			case <-c.stop:
 				timer.Stop()
+				cancel()
 				c.logger.Info("stop")
 				return
 
@@ -299,8 +333,23 @@ func (c *Cron) run() {
 	}
 }
 
-// startJob在新的goroutine中运行给定的作业。
-func (c *Cron) startJob(j Job) {
+// nextAfter返回schedule在本次激活时间prev之后、晚于唤醒的时间now的下一次激活时间。
+// 时间表通常从now开始计算。PreciseDelaySchedule则返回prev + k*Delay中晚于now的第一个，
+// 这样亚秒级的间隔不会因为唤醒的延迟而逐渐漂移，已经错过的激活时间则被跳过。
+func nextAfter(schedule Schedule, prev, now time.Time) time.Time {
+	precise, ok := schedule.(PreciseDelaySchedule)
+	if !ok || precise.Delay <= 0 {
+		return schedule.Next(now)
+	}
+	return gridAfter(prev, precise.Delay, now)
+}
+
+// startJob在新的goroutine中运行条目在激活时间scheduled的作业。
+func (c *Cron) startJob(e *Entry, scheduled time.Time) {
+	j := e.WrappedJob
+	if ctxJob, ok := e.Job.(*contextJob); ok {
+		j = ctxJob.activation(j, scheduled)
+	}
 	c.jobWaiter.Add(1)
 	go func() {
 		defer c.jobWaiter.Done()
@@ -314,6 +363,7 @@ func (c *Cron) now() time.Time {
 }
 
 // Stop 停止cron调度程序，如果它在运行的话，否则不做任何操作。
+// 正在运行的JobWithContext的上下文会被取消。
 // 返回上下文，以便调用方可以等待正在运行的作业完成。
 func (c *Cron) Stop() context.Context {
 	c.runningMu.Lock()
@@ -344,7 +394,16 @@ func (c *Cron) removeEntry(id EntryID) {
 	for _, e := range c.entries {
 		if e.ID != id {
 			entries = append(entries, e)
+		} else if j, ok := e.Job.(*contextJob); ok {
+			j.stop()
 		}
 	}
 	c.entries = entries
 }
+
+// startContext为JobWithContext条目从调度程序的上下文ctx派生条目的上下文。
+func startContext(ctx context.Context, e *Entry) {
+	if j, ok := e.Job.(*contextJob); ok {
+		j.start(ctx)
+	}
+}
@@ -555,6 +555,46 @@ func TestJobWithZeroTimeDoesNotRun(t *testing.T) {
 	}
 }
 
+// Tests that @reboot jobs run on the first Start only and EveryStart jobs on every Start
+func TestReboot(t *testing.T) {
+	var once, every, added int32
+	cron := New()
+	if _, err := cron.AddFunc("@reboot", func() { atomic.AddInt32(&once, 1) }); err != nil {
+		t.Fatal(err)
+	}
+	cron.Schedule(RebootSchedule{EveryStart: true}, FuncJob(func() { atomic.AddInt32(&every, 1) }))
+
+	for i := 1; i <= 3; i++ {
+		cron.Start()
+		time.Sleep(50 * time.Millisecond)
+		<-cron.Stop().Done()
+		if actual := atomic.LoadInt32(&once); actual != 1 {
+			t.Errorf("start %d: expected @reboot to have run once, got %d", i, actual)
+		}
+		if actual := atomic.LoadInt32(&every); actual != int32(i) {
+			t.Errorf("start %d: expected EveryStart to have run %d times, got %d", i, i, actual)
+		}
+	}
+
+	// Entries added to a running Cron run immediately, and only once.
+	cron.Start()
+	cron.AddFunc("@reboot", func() { atomic.AddInt32(&added, 1) })
+	time.Sleep(50 * time.Millisecond)
+	<-cron.Stop().Done()
+	cron.Start()
+	time.Sleep(50 * time.Millisecond)
+	<-cron.Stop().Done()
+	if actual := atomic.LoadInt32(&added); actual != 1 {
+		t.Errorf("expected @reboot added while running to run once, got %d", actual)
+	}
+
+	for _, entry := range cron.Entries() {
+		if !entry.Next.IsZero() {
+			t.Errorf("entry %d: expected no further activations, got %v", entry.ID, entry.Next)
+		}
+	}
+}
+
 func TestStopAndWait(t *testing.T) {
 	t.Run("nothing running, returns immediately", func(t *testing.T) {
 		cron := newWithSeconds()
@@ -700,3 +740,51 @@ func stop(cron *Cron) chan bool {
 func newWithSeconds() *Cron {
 	return New(WithParser(secondParser), WithChain())
 }
+
+// Sub-second schedules are computed from the previous activation rather than
+// from the time the scheduler woke up, so they stay on their grid.
+func TestSubSecondScheduleDoesNotDrift(t *testing.T) {
+	var (
+		sched = EveryPrecisely(50 * time.Millisecond)
+		start = getTime("2012-07-09T10:00:00-0000")
+		next  = sched.Next(start)
+	)
+	// Each wake-up comes a little late, and some miss the following activation.
+	for i, late := range []time.Duration{3, 7, 1, 60, 12, 49, 120, 2} {
+		prev := next
+		next = nextAfter(sched, prev, prev.Add(late*time.Millisecond))
+		if d := next.Sub(start); d%(50*time.Millisecond) != 0 {
+			t.Fatalf("wake-up %d: expected activations on a 50ms grid, drifted by %v", i, d%(50*time.Millisecond))
+		}
+		if !next.After(prev.Add(late * time.Millisecond)) {
+			t.Fatalf("wake-up %d: expected an activation after the wake-up, got %v", i, next)
+		}
+	}
+	if expected := start.Add(600 * time.Millisecond); !next.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, next)
+	}
+}
+
+func TestNextAfter(t *testing.T) {
+	prev := getTime("2012-07-09T10:00:00-0000")
+	tests := []struct {
+		schedule Schedule
+		now      string
+		expected string
+	}{
+		// Schedules other than PreciseDelaySchedule are computed from the wake time.
+		{Every(5 * time.Second), "2012-07-09T10:00:01.3-0000", "2012-07-09T10:00:06-0000"},
+		{Every(5 * time.Second), "2012-07-09T10:00:00.1-0000", "2012-07-09T10:00:05-0000"},
+
+		// PreciseDelaySchedule keeps to the grid of prev, skipping missed activations.
+		{EveryPrecisely(500 * time.Millisecond), "2012-07-09T10:00:00.1-0000", "2012-07-09T10:00:00.5-0000"},
+		{EveryPrecisely(500 * time.Millisecond), "2012-07-09T10:00:00.7-0000", "2012-07-09T10:00:01-0000"},
+		{EveryPrecisely(500 * time.Millisecond), "2012-07-09T10:00:01-0000", "2012-07-09T10:00:01.5-0000"},
+	}
+	for _, test := range tests {
+		actual := nextAfter(test.schedule, prev, getTime(test.now))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%v at %s => expected %v, got %v", test.schedule, test.now, expected, actual)
+		}
+	}
+}
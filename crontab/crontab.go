@@ -0,0 +1,235 @@
+// Package crontab解析crontab文件，并将其中的作业注册到cron.Cron上。
+//
+// 支持的格式与Vixie cron及cronie相同：
+//   - 空行和以#开头的行被忽略
+//   - NAME=value形式的行为环境变量赋值，对之后的作业生效；值可以用引号括起来。
+//     CRON_TZ指定之后的作业的时区，SHELL和MAILTO等其他变量原样记录在Entry.Env中
+//   - 其余的行为作业：时间表之后是命令文本，时间表可以是字段或描述符（包括@reboot）
+package crontab
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Reboot是在cron启动时运行一次的作业的时间表。
+const Reboot = "@reboot"
+
+// Entry是crontab文件中的一个作业。
+type Entry struct {
+	// Line是该作业在文件中的行号，从1开始。
+	Line int
+
+	// Spec是该行的时间表部分，例如"*/5 * * * *"或"@daily"。
+	Spec string
+
+	// Schedule是解析Spec（以及之前的CRON_TZ）得到的时间表，Spec为@reboot时为cron.RebootSchedule。
+	Schedule cron.Schedule
+
+	// Command是时间表之后的命令文本，去掉了首尾的空白。
+	Command string
+
+	// Env是该行之前的环境变量赋值，例如SHELL、MAILTO和CRON_TZ。
+	Env map[string]string
+}
+
+// Error是crontab文件中某一行的错误。
+type Error struct {
+	Line int   // 出错的行号，从1开始
+	Err  error // 出错的原因，解析时间表出错时为*cron.ParseError
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("crontab line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap返回出错的原因，以便通过errors.As获取*cron.ParseError。
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Parser解析crontab文件。零值使用标准的5个字段，并接受描述符。
+type Parser struct {
+	// Fields是时间表的字段数，为0时为5；使用带有秒字段的Schedules时应设为6。
+	Fields int
+
+	// Schedules用于解析每行的时间表，为nil时使用标准解析器（包括描述符）。
+	Schedules cron.ScheduleParser
+}
+
+// standardParser是Parser.Schedules的默认值。
+var standardParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// maxDescriptorFields是带参数的描述符（例如"@every 1h from 09:00"）最多占用的字段数。
+const maxDescriptorFields = 4
+
+// envAssignment匹配环境变量赋值，例如"MAILTO = ops@example.com"。
+var envAssignment = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+
+// Parse使用默认的Parser解析r中的crontab文件。
+func Parse(r io.Reader) ([]Entry, error) {
+	return Parser{}.Parse(r)
+}
+
+// Parse解析r中的crontab文件，返回其中的作业。遇到第一个错误时返回*Error。
+func (p Parser) Parse(r io.Reader) ([]Entry, error) {
+	var (
+		entries []Entry
+		env     = map[string]string{}
+		scanner = bufio.NewScanner(r)
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if m := envAssignment.FindStringSubmatch(text); m != nil {
+			name, value := m[1], unquote(m[2])
+			if name == "CRON_TZ" {
+				if _, err := time.LoadLocation(value); err != nil {
+					return nil, &Error{line, fmt.Errorf("bad CRON_TZ %s: %v", value, err)}
+				}
+			}
+			env = copyEnv(env)
+			env[name] = value
+			continue
+		}
+
+		entry, err := p.parseEntry(text, env)
+		if err != nil {
+			return nil, &Error{line, err}
+		}
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseEntry将作业行text拆分为时间表和命令文本，并解析时间表。
+func (p Parser) parseEntry(text string, env map[string]string) (Entry, error) {
+	fields, offsets := splitFields(text)
+	entry := Entry{Env: env}
+	if fields[0] == Reboot {
+		entry.Spec, entry.Schedule = Reboot, cron.RebootSchedule{}
+		entry.Command = strings.TrimSpace(text[len(Reboot):])
+		if entry.Command == "" {
+			return Entry{}, fmt.Errorf("missing command: %s", text)
+		}
+		return entry, nil
+	}
+
+	parser := p.Schedules
+	if parser == nil {
+		parser = standardParser
+	}
+	prefix := ""
+	if tz, ok := env["CRON_TZ"]; ok {
+		prefix = "CRON_TZ=" + tz + " "
+	}
+	parse := func(n int) (cron.Schedule, error) {
+		entry.Spec = text[:offsets[n-1]+len(fields[n-1])]
+		return parser.Parse(prefix + entry.Spec)
+	}
+
+	// 描述符的参数个数不定，使用能够解析的最长的前几个字段，但至少留下一个字段作为命令。
+	var err error
+	if strings.HasPrefix(fields[0], "@") {
+		for n := maxDescriptorFields; n > 1; n-- {
+			if n < len(fields) {
+				if entry.Schedule, err = parse(n); err == nil {
+					break
+				}
+			}
+		}
+		if entry.Schedule == nil {
+			entry.Schedule, err = parse(1)
+		}
+	} else {
+		n := p.Fields
+		if n == 0 {
+			n = 5
+		}
+		if len(fields) <= n {
+			return Entry{}, fmt.Errorf("expected %d time fields and a command: %s", n, text)
+		}
+		entry.Schedule, err = parse(n)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Command = strings.TrimSpace(text[len(entry.Spec):])
+	if entry.Command == "" {
+		return Entry{}, fmt.Errorf("missing command: %s", text)
+	}
+	return entry, nil
+}
+
+// splitFields与strings.Fields相同，但同时返回每个字段在s中的字节偏移。
+func splitFields(s string) (fields []string, offsets []int) {
+	start := -1
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			fields, offsets = append(fields, s[start:i]), append(offsets, start)
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields, offsets = append(fields, s[start:]), append(offsets, start)
+	}
+	return fields, offsets
+}
+
+// unquote去掉值两端成对的单引号或双引号。
+func unquote(value string) string {
+	if n := len(value); n >= 2 && (value[0] == '"' || value[0] == '\'') && value[n-1] == value[0] {
+		return value[1 : n-1]
+	}
+	return value
+}
+
+// copyEnv返回env的副本，使之前的作业不受之后的赋值影响。
+func copyEnv(env map[string]string) map[string]string {
+	result := make(map[string]string, len(env)+1)
+	for k, v := range env {
+		result[k] = v
+	}
+	return result
+}
+
+// JobFactory将crontab中的一个作业（通常是它的Command和Env）转换为cron.Job。
+type JobFactory func(entry Entry) (cron.Job, error)
+
+// Register使用factory为entries中的每个作业创建cron.Job，并注册到c上，返回各个条目的ID。
+// 只有在所有作业都创建成功后才会注册；factory的错误以*Error返回。
+// @reboot作业在c第一次启动时运行一次，如果c已经启动则立即运行。
+func Register(c *cron.Cron, entries []Entry, factory JobFactory) ([]cron.EntryID, error) {
+	jobs := make([]cron.Job, len(entries))
+	for i, entry := range entries {
+		job, err := factory(entry)
+		if err != nil {
+			return nil, &Error{entry.Line, err}
+		}
+		jobs[i] = job
+	}
+
+	ids := make([]cron.EntryID, len(entries))
+	for i, entry := range entries {
+		ids[i] = c.Schedule(entry.Schedule, jobs[i])
+	}
+	return ids, nil
+}
@@ -0,0 +1,179 @@
+package crontab
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const sample = `# m h dom mon dow command
+SHELL=/bin/bash
+MAILTO="ops@example.com"
+
+*/5 * * * *   /usr/bin/check --quiet   "a  b"
+	# indented comment
+@daily        /usr/bin/rotate-logs
+CRON_TZ=Asia/Tokyo
+30 4 * * mon-fri  report.sh > /tmp/report.log 2>&1
+@every 1h from 09:15 poll.sh
+@reboot /usr/bin/warm-cache
+`
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := map[string]string{"SHELL": "/bin/bash", "MAILTO": "ops@example.com"}
+	tokyo := map[string]string{"SHELL": "/bin/bash", "MAILTO": "ops@example.com", "CRON_TZ": "Asia/Tokyo"}
+	expected := []struct {
+		line    int
+		spec    string
+		command string
+		env     map[string]string
+	}{
+		{5, "*/5 * * * *", `/usr/bin/check --quiet   "a  b"`, base},
+		{7, "@daily", "/usr/bin/rotate-logs", base},
+		{9, "30 4 * * mon-fri", "report.sh > /tmp/report.log 2>&1", tokyo},
+		{10, "@every 1h from 09:15", "poll.sh", tokyo},
+		{11, "@reboot", "/usr/bin/warm-cache", tokyo},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i, e := range expected {
+		actual := entries[i]
+		if actual.Line != e.line || actual.Spec != e.spec || actual.Command != e.command || !reflect.DeepEqual(actual.Env, e.env) {
+			t.Errorf("entry %d: expected %d %q %q %v, got %d %q %q %v", i,
+				e.line, e.spec, e.command, e.env,
+				actual.Line, actual.Spec, actual.Command, actual.Env)
+		}
+		if _, reboot := actual.Schedule.(cron.RebootSchedule); actual.Schedule == nil || reboot != (e.spec == Reboot) {
+			t.Errorf("entry %d: unexpected schedule %v", i, actual.Schedule)
+		}
+	}
+
+	// CRON_TZ applies to the entries that follow it.
+	from := time.Date(2012, time.July, 9, 0, 0, 0, 0, time.UTC)
+	if next := entries[2].Schedule.Next(from); !next.Equal(time.Date(2012, time.July, 9, 19, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected the schedule in Asia/Tokyo, got %v", next)
+	}
+}
+
+func TestParseSeconds(t *testing.T) {
+	p := Parser{
+		Fields:    6,
+		Schedules: cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow),
+	}
+	entries, err := p.Parse(strings.NewReader("*/10 * * * * * tick\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Spec != "*/10 * * * * *" || entries[0].Command != "tick" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		crontab string
+		line    int
+		err     string
+	}{
+		{"# ok\n0 25 * * * cmd\n", 2, "hour field"},
+		{"\n\n* * * *\n", 3, "expected 5 time fields and a command"},
+		{"* * * * *\n", 1, "expected 5 time fields and a command"},
+		{"@daily\n", 1, "missing command"},
+		{"@reboot   \n", 1, "missing command"},
+		{"@fortnightly cmd\n", 1, "unrecognized descriptor"},
+		{"CRON_TZ=Mars/Olympus\n* * * * * cmd\n", 1, "bad CRON_TZ"},
+	}
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.crontab))
+		var cerr *Error
+		if !errors.As(err, &cerr) || cerr.Line != test.line || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%q => expected line %d: %s, got %v", test.crontab, test.line, test.err, err)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), fmt.Sprintf("crontab line %d: ", test.line)) {
+			t.Errorf("%q => expected the line number in %q", test.crontab, err)
+		}
+	}
+
+	// Schedule errors are available as *cron.ParseError.
+	_, err := Parse(strings.NewReader("0 25 * * * cmd\n"))
+	var perr *cron.ParseError
+	if !errors.As(err, &perr) || perr.Reason != cron.ReasonOutOfRange {
+		t.Errorf("expected a *cron.ParseError, got %v", err)
+	}
+}
+
+type recordingJob struct {
+	command string
+	ran     chan<- string
+}
+
+func (j recordingJob) Run() { j.ran <- j.command }
+
+func TestRegister(t *testing.T) {
+	entries, err := Parse(strings.NewReader("@every 1s tick\n@reboot boot\n@hourly hourly\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ran := make(chan string, 10)
+	c := cron.New()
+	ids, err := Register(c, entries, func(e Entry) (cron.Job, error) {
+		return recordingJob{e.Command, ran}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || len(c.Entries()) != 3 {
+		t.Fatalf("expected 3 entries, got %v", ids)
+	}
+
+	c.Start()
+	defer c.Stop()
+	counts := map[string]int{}
+	timeout := time.After(2500 * time.Millisecond)
+LOOP:
+	for {
+		select {
+		case command := <-ran:
+			counts[command]++
+		case <-timeout:
+			break LOOP
+		}
+	}
+	if counts["boot"] != 1 || counts["tick"] < 2 || counts["hourly"] != 0 {
+		t.Errorf("unexpected runs %v", counts)
+	}
+}
+
+func TestRegisterFactoryError(t *testing.T) {
+	entries, err := Parse(strings.NewReader("@daily ok\n\n@daily fail\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cron.New()
+	_, err = Register(c, entries, func(e Entry) (cron.Job, error) {
+		if e.Command == "fail" {
+			return nil, errors.New("unknown command")
+		}
+		return cron.FuncJob(func() {}), nil
+	})
+	var cerr *Error
+	if !errors.As(err, &cerr) || cerr.Line != 3 || err.Error() != "crontab line 3: unknown command" {
+		t.Errorf("expected an error on line 3, got %v", err)
+	}
+	if len(c.Entries()) != 0 {
+		t.Errorf("expected nothing to be registered, got %d entries", len(c.Entries()))
+	}
+}
@@ -0,0 +1,534 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message标识Describe使用的一条消息。Catalog为每条消息提供一个fmt格式字符串。
+type Message int
+
+// Describe使用的消息。注释中给出了英文目录的格式字符串。
+const (
+	MsgEverySecond    Message = iota // "every second"
+	MsgEveryNSeconds                 // "every %d seconds"
+	MsgAtSecond                      // "at second %s"
+	MsgAtSeconds                     // "at seconds %s"
+	MsgEveryMinute                   // "every minute"
+	MsgEveryNMinutes                 // "every %d minutes"
+	MsgOfEveryMinute                 // "of every minute"
+	MsgAtMinute                      // "at minute %s"
+	MsgAtMinutes                     // "at minutes %s"
+	MsgEveryNHours                   // "every %d hours"
+	MsgOfEveryHour                   // "of every hour"
+	MsgBetween                       // "between %s and %s"
+	MsgDuringHours                   // "during hours %s"
+	MsgAtTimes                       // "at %s"
+	MsgThrough                       // "%s through %s"
+	MsgOnDay                         // "on day %s of the month"
+	MsgOnDays                        // "on days %s of the month"
+	MsgLastDay                       // "on the last day of the month"
+	MsgLastDayMinus                  // "on the last day of the month minus %d"
+	MsgNearestWeekday                // "on the weekday nearest day %d of the month"
+	MsgLastWeekday                   // "on the last weekday of the month"
+	MsgLastDayOfWeek                 // "on the last %s of the month"
+	MsgNthDayOfWeek                  // "on the %s %s of the month"
+	MsgOr                            // "%s or %s"
+	MsgInMonths                      // "in %s"
+	MsgInYears                       // "in %s"
+	MsgTimeZone                      // "(%s time)"
+	MsgEvery                         // "every %s"
+	MsgOnlyIf                        // "%s, only if it is %s"
+)
+
+// Phrases是描述的各个组成部分，由Catalog.Sentence组合成完整的句子。
+// 未使用的部分为空字符串。
+type Phrases struct {
+	Seconds, Minutes, Hours string
+	Days, Months, Years     string
+	Location                string
+}
+
+// Catalog是Describer使用的消息目录，实现它即可用其他语言描述时间表。
+// 此软件包提供了English和Chinese两种实现。
+type Catalog interface {
+	// Message返回给定消息的fmt格式字符串。
+	Message(id Message) string
+	// Weekday返回星期几的名称。
+	Weekday(d time.Weekday) string
+	// Month返回月份的名称。
+	Month(m time.Month) string
+	// Ordinal返回序数词，n从1开始，例如"second"。
+	Ordinal(n int) string
+	// Duration描述一段时间，例如"1 hour and 30 minutes"。
+	Duration(d time.Duration) string
+	// List将多个并列的项目连接起来，例如"a, b and c"。
+	List(items []string) string
+	// Sentence按照该语言的语序将各部分组合成完整的描述。
+	Sentence(p Phrases) string
+}
+
+// English是英文的消息目录。
+var English Catalog = englishCatalog{}
+
+// Chinese是中文的消息目录。
+var Chinese Catalog = chineseCatalog{}
+
+// Describer使用给定的消息目录生成时间表的可读描述。
+type Describer struct {
+	catalog Catalog
+}
+
+// NewDescriber创建一个使用给定消息目录的Describer。
+func NewDescriber(catalog Catalog) Describer {
+	return Describer{catalog}
+}
+
+var englishDescriber = NewDescriber(English)
+
+// Describe返回时间表的英文描述，例如"*/15 9-17 * * mon-fri"的描述为
+// "every 15 minutes between 09:00 and 17:59, Monday through Friday"。
+func Describe(schedule Schedule) (string, error) {
+	return englishDescriber.Describe(schedule)
+}
+
+// Describe返回时间表的描述。
+// 它支持SpecSchedule、ConstantDelaySchedule和PreciseDelaySchedule（包括由描述符得到的时间表），
+// 对于其他类型的时间表返回错误。
+func (d Describer) Describe(schedule Schedule) (string, error) {
+	switch s := schedule.(type) {
+	case *SpecSchedule:
+		if s.Millisecond != nil {
+			return "", fmt.Errorf("cannot describe schedule with milliseconds: %v", s)
+		}
+		return d.describeSpec(s), nil
+	case ConstantDelaySchedule:
+		return d.msg(MsgEvery, d.catalog.Duration(s.Delay)), nil
+	case PreciseDelaySchedule:
+		return d.msg(MsgEvery, d.catalog.Duration(s.Delay)), nil
+	}
+	return "", fmt.Errorf("cannot describe schedule of type %T", schedule)
+}
+
+// msg使用给定的参数格式化一条消息。
+func (d Describer) msg(id Message, args ...interface{}) string {
+	return fmt.Sprintf(d.catalog.Message(id), args...)
+}
+
+func (d Describer) describeSpec(s *SpecSchedule) string {
+	var p Phrases
+	d.describeTime(s, &p)
+	p.Days = d.describeDays(s)
+	if s.Month&starBit == 0 {
+		p.Months = d.msg(MsgInMonths, d.describeValues(fieldValues(s.Month, months), func(m int) string {
+			return d.catalog.Month(time.Month(m))
+		}))
+	}
+	if s.Year != nil {
+		p.Years = d.msg(MsgInYears, d.describeValues(s.Year, func(y int) string { return fmt.Sprint(y) }))
+	}
+	if s.Location != nil && s.Location != time.Local {
+		p.Location = d.msg(MsgTimeZone, s.Location.String())
+	}
+	return d.catalog.Sentence(p)
+}
+
+// describeTime描述秒、分钟和小时字段。
+func (d Describer) describeTime(s *SpecSchedule, p *Phrases) {
+	var (
+		secs  = fieldValues(s.Second, seconds)
+		mins  = fieldValues(s.Minute, minutes)
+		hrs   = fieldValues(s.Hour, hours)
+		every bool // 秒或分钟字段是否已描述为"每…"
+	)
+
+	// 秒和分钟都是单个值时，直接列出具体的时刻，例如"at 09:30 and 17:30"。
+	if len(secs) == 1 && len(mins) == 1 && s.Hour&starBit == 0 && len(hrs) <= 6 {
+		var times []string
+		for _, h := range hrs {
+			times = append(times, clock(h, mins[0], secs[0]))
+		}
+		p.Hours = d.msg(MsgAtTimes, d.catalog.List(times))
+		return
+	}
+
+	number := func(v int) string { return fmt.Sprint(v) }
+	switch step := progressionStep(secs, seconds); {
+	case step == 1:
+		p.Seconds, every = d.msg(MsgEverySecond), true
+	case step > 1:
+		p.Seconds, every = d.msg(MsgEveryNSeconds, step), true
+	case len(secs) == 1 && secs[0] == 0:
+	case len(secs) == 1:
+		p.Seconds = d.msg(MsgAtSecond, d.describeValues(secs, number))
+	default:
+		p.Seconds = d.msg(MsgAtSeconds, d.describeValues(secs, number))
+	}
+
+	switch step := progressionStep(mins, minutes); {
+	case step == 1:
+		switch {
+		case every:
+		case p.Seconds != "":
+			p.Minutes, every = d.msg(MsgOfEveryMinute), true
+		default:
+			p.Minutes, every = d.msg(MsgEveryMinute), true
+		}
+	case step > 1:
+		p.Minutes, every = d.msg(MsgEveryNMinutes, step), true
+	case len(mins) == 1:
+		p.Minutes, every = d.msg(MsgAtMinute, d.describeValues(mins, number)), false
+	default:
+		p.Minutes, every = d.msg(MsgAtMinutes, d.describeValues(mins, number)), false
+	}
+
+	switch step := progressionStep(hrs, hours); {
+	case step == 1:
+		if !every {
+			p.Hours = d.msg(MsgOfEveryHour)
+		}
+	case step > 1:
+		p.Hours = d.msg(MsgEveryNHours, step)
+	case len(valueRuns(hrs)) == 1:
+		p.Hours = d.msg(MsgBetween, clock(hrs[0], 0, 0), clock(hrs[len(hrs)-1], 59, 0))
+	default:
+		p.Hours = d.msg(MsgDuringHours, d.describeValues(hrs, number))
+	}
+}
+
+// describeDays描述Dom和Dow字段及其修饰符。
+func (d Describer) describeDays(s *SpecSchedule) string {
+	var domPhrases, dowPhrases []string
+	if s.Dom&starBit == 0 {
+		days := fieldValues(s.Dom, dom)
+		list := d.describeValues(days, func(v int) string { return fmt.Sprint(v) })
+		switch len(days) {
+		case 0:
+		case 1:
+			domPhrases = append(domPhrases, d.msg(MsgOnDay, list))
+		default:
+			domPhrases = append(domPhrases, d.msg(MsgOnDays, list))
+		}
+	}
+	for i := uint(0); i <= dom.max-dom.min; i++ {
+		switch {
+		case 1<<i&s.DomLast == 0:
+		case i == 0:
+			domPhrases = append(domPhrases, d.msg(MsgLastDay))
+		default:
+			domPhrases = append(domPhrases, d.msg(MsgLastDayMinus, i))
+		}
+	}
+	if s.DomWeekday&1 > 0 {
+		domPhrases = append(domPhrases, d.msg(MsgLastWeekday))
+	}
+	for day := dom.min; day <= dom.max; day++ {
+		if 1<<day&s.DomWeekday > 0 {
+			domPhrases = append(domPhrases, d.msg(MsgNearestWeekday, day))
+		}
+	}
+
+	if s.Dow&starBit == 0 {
+		if weekdays := d.describeWeekdays(fieldValues(s.Dow, dow)); weekdays != "" {
+			dowPhrases = append(dowPhrases, weekdays)
+		}
+	}
+	for day := dow.min; day <= dow.max; day++ {
+		if 1<<day&s.DowLast > 0 {
+			dowPhrases = append(dowPhrases, d.msg(MsgLastDayOfWeek, d.catalog.Weekday(time.Weekday(day))))
+		}
+	}
+	for i := uint(0); i < 5*7; i++ {
+		if 1<<i&s.DowNth > 0 {
+			dowPhrases = append(dowPhrases, d.msg(MsgNthDayOfWeek,
+				d.catalog.Ordinal(int(i/7)+1), d.catalog.Weekday(time.Weekday(i%7))))
+		}
+	}
+
+	if s.DomAndDow && len(domPhrases) > 0 && len(dowPhrases) > 0 {
+		return d.msg(MsgOnlyIf, d.or(domPhrases), d.or(dowPhrases))
+	}
+	return d.or(append(domPhrases, dowPhrases...))
+}
+
+// describeWeekdays列出星期几，连续三天以上的写成范围，并允许跨过周六到周日，
+// 例如"Friday through Monday"。
+func (d Describer) describeWeekdays(days []int) string {
+	if len(days) == 0 {
+		return ""
+	}
+	var set [7]bool
+	for _, day := range days {
+		set[day] = true
+	}
+	// 从一个前一天不在集合中的日子开始，这样跨周的范围不会被拆开。
+	first := 0
+	for i := 0; i < 7; i++ {
+		if set[i] && !set[(i+6)%7] {
+			first = i
+			break
+		}
+	}
+	var rotated []int
+	for i := 0; i < 7; i++ {
+		if day := (first + i) % 7; set[day] {
+			rotated = append(rotated, first+i)
+		}
+	}
+	return d.describeValues(rotated, func(v int) string {
+		return d.catalog.Weekday(time.Weekday(v % 7))
+	})
+}
+
+// describeValues列出升序的值，连续三个以上的值写成范围，例如"1, 5 through 10 and 20"。
+func (d Describer) describeValues(values []int, name func(int) string) string {
+	var items []string
+	for _, run := range valueRuns(values) {
+		first, last := run[0], run[len(run)-1]
+		if len(run) >= 3 {
+			items = append(items, d.msg(MsgThrough, name(first), name(last)))
+			continue
+		}
+		for _, v := range run {
+			items = append(items, name(v))
+		}
+	}
+	return d.catalog.List(items)
+}
+
+// or使用MsgOr连接多个可以任选其一的短语。
+func (d Describer) or(phrases []string) string {
+	var result string
+	for i, phrase := range phrases {
+		if i == 0 {
+			result = phrase
+		} else {
+			result = d.msg(MsgOr, result, phrase)
+		}
+	}
+	return result
+}
+
+// valueRuns将升序的值拆分为连续的区间。
+func valueRuns(values []int) [][]int {
+	var runs [][]int
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		runs = append(runs, values[i:j+1])
+		i = j + 1
+	}
+	return runs
+}
+
+// progressionStep返回values作为从r.min开始、直到r.max的等差数列的步长。
+// 如果values不是这样的数列，则返回0。
+func progressionStep(values []int, r bounds) int {
+	if len(values) < 2 || values[0] != int(r.min) {
+		return 0
+	}
+	step := values[1] - values[0]
+	for i := 2; i < len(values); i++ {
+		if values[i]-values[i-1] != step {
+			return 0
+		}
+	}
+	if values[len(values)-1]+step <= int(r.max) {
+		return 0
+	}
+	return step
+}
+
+// clock将时刻格式化为"15:04"，秒不为零时格式化为"15:04:05"。
+func clock(hour, minute, second int) string {
+	if second != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)
+	}
+	return fmt.Sprintf("%02d:%02d", hour, minute)
+}
+
+type englishCatalog struct{}
+
+var englishMessages = map[Message]string{
+	MsgEverySecond:    "every second",
+	MsgEveryNSeconds:  "every %d seconds",
+	MsgAtSecond:       "at second %s",
+	MsgAtSeconds:      "at seconds %s",
+	MsgEveryMinute:    "every minute",
+	MsgEveryNMinutes:  "every %d minutes",
+	MsgOfEveryMinute:  "of every minute",
+	MsgAtMinute:       "at minute %s",
+	MsgAtMinutes:      "at minutes %s",
+	MsgEveryNHours:    "every %d hours",
+	MsgOfEveryHour:    "of every hour",
+	MsgBetween:        "between %s and %s",
+	MsgDuringHours:    "during hours %s",
+	MsgAtTimes:        "at %s",
+	MsgThrough:        "%s through %s",
+	MsgOnDay:          "on day %s of the month",
+	MsgOnDays:         "on days %s of the month",
+	MsgLastDay:        "on the last day of the month",
+	MsgLastDayMinus:   "on the last day of the month minus %d",
+	MsgNearestWeekday: "on the weekday nearest day %d of the month",
+	MsgLastWeekday:    "on the last weekday of the month",
+	MsgLastDayOfWeek:  "on the last %s of the month",
+	MsgNthDayOfWeek:   "on the %s %s of the month",
+	MsgOr:             "%s or %s",
+	MsgInMonths:       "in %s",
+	MsgInYears:        "in %s",
+	MsgTimeZone:       "(%s time)",
+	MsgEvery:          "every %s",
+	MsgOnlyIf:         "%s, only if it is %s",
+}
+
+var englishOrdinals = []string{"first", "second", "third", "fourth", "fifth"}
+
+func (englishCatalog) Message(id Message) string     { return englishMessages[id] }
+func (englishCatalog) Weekday(d time.Weekday) string { return d.String() }
+func (englishCatalog) Month(m time.Month) string     { return m.String() }
+
+func (englishCatalog) Ordinal(n int) string {
+	if n >= 1 && n <= len(englishOrdinals) {
+		return englishOrdinals[n-1]
+	}
+	return fmt.Sprintf("%dth", n)
+}
+
+func (c englishCatalog) Duration(d time.Duration) string {
+	var parts []string
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{
+		{time.Hour, "hour"},
+		{time.Minute, "minute"},
+		{time.Second, "second"},
+		{time.Millisecond, "millisecond"},
+	} {
+		n := int64(d / unit.size)
+		d -= time.Duration(n) * unit.size
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+unit.name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, unit.name))
+		}
+	}
+	if len(parts) == 0 {
+		return d.String()
+	}
+	return c.List(parts)
+}
+
+func (englishCatalog) List(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func (englishCatalog) Sentence(p Phrases) string {
+	sentence := joinNonEmpty(", ",
+		joinNonEmpty(" ", p.Seconds, p.Minutes, p.Hours),
+		p.Days, p.Months, p.Years)
+	return joinNonEmpty(" ", sentence, p.Location)
+}
+
+type chineseCatalog struct{}
+
+var chineseMessages = map[Message]string{
+	MsgEverySecond:    "每秒",
+	MsgEveryNSeconds:  "每%d秒",
+	MsgAtSecond:       "第%s秒",
+	MsgAtSeconds:      "第%s秒",
+	MsgEveryMinute:    "每分钟",
+	MsgEveryNMinutes:  "每%d分钟",
+	MsgOfEveryMinute:  "每分钟的",
+	MsgAtMinute:       "第%s分钟",
+	MsgAtMinutes:      "第%s分钟",
+	MsgEveryNHours:    "每%d小时",
+	MsgOfEveryHour:    "每小时的",
+	MsgBetween:        "%s至%s之间",
+	MsgDuringHours:    "%s点",
+	MsgAtTimes:        "%s",
+	MsgThrough:        "%s至%s",
+	MsgOnDay:          "每月%s日",
+	MsgOnDays:         "每月%s日",
+	MsgLastDay:        "每月最后一天",
+	MsgLastDayMinus:   "每月最后一天的前%d天",
+	MsgNearestWeekday: "每月离%d日最近的工作日",
+	MsgLastWeekday:    "每月最后一个工作日",
+	MsgLastDayOfWeek:  "每月最后一个%s",
+	MsgNthDayOfWeek:   "每月第%s个%s",
+	MsgOr:             "%s或%s",
+	MsgInMonths:       "%s",
+	MsgInYears:        "%s年",
+	MsgTimeZone:       "%s时间",
+	MsgEvery:          "每%s",
+	MsgOnlyIf:         "%s，且为%s",
+}
+
+var (
+	chineseWeekdays = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+	chineseOrdinals = []string{"一", "二", "三", "四", "五"}
+)
+
+func (chineseCatalog) Message(id Message) string     { return chineseMessages[id] }
+func (chineseCatalog) Weekday(d time.Weekday) string { return chineseWeekdays[d] }
+func (chineseCatalog) Month(m time.Month) string     { return fmt.Sprintf("%d月", m) }
+
+func (chineseCatalog) Ordinal(n int) string {
+	if n >= 1 && n <= len(chineseOrdinals) {
+		return chineseOrdinals[n-1]
+	}
+	return fmt.Sprint(n)
+}
+
+func (chineseCatalog) Duration(d time.Duration) string {
+	var sb strings.Builder
+	for _, unit := range []struct {
+		size time.Duration
+		name string
+	}{
+		{time.Hour, "小时"},
+		{time.Minute, "分钟"},
+		{time.Second, "秒"},
+		{time.Millisecond, "毫秒"},
+	} {
+		if n := int64(d / unit.size); n > 0 {
+			fmt.Fprintf(&sb, "%d%s", n, unit.name)
+			d -= time.Duration(n) * unit.size
+		}
+	}
+	if sb.Len() == 0 {
+		return d.String()
+	}
+	return sb.String()
+}
+
+func (chineseCatalog) List(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], "、") + "和" + items[len(items)-1]
+}
+
+func (chineseCatalog) Sentence(p Phrases) string {
+	return joinNonEmpty("，",
+		p.Location, p.Years, p.Months, p.Days,
+		joinNonEmpty("", p.Hours, p.Minutes, p.Seconds))
+}
+
+// joinNonEmpty使用sep连接所有非空的字符串。
+func joinNonEmpty(sep string, elems ...string) string {
+	var nonEmpty []string
+	for _, elem := range elems {
+		if elem != "" {
+			nonEmpty = append(nonEmpty, elem)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
@@ -0,0 +1,131 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	parser := NewParser(SecondOptional | Minute | Hour | Dom | Month | Dow | Descriptor | QuartzModifiers)
+	tests := []struct {
+		spec, english, chinese string
+	}{
+		{"*/15 9-17 * * mon-fri",
+			"every 15 minutes between 09:00 and 17:59, Monday through Friday",
+			"周一至周五，09:00至17:59之间每15分钟"},
+		{"* * * * *", "every minute", "每分钟"},
+		{"*/10 * * * * *", "every 10 seconds", "每10秒"},
+		{"30 * * * * *", "at second 30 of every minute", "每分钟的第30秒"},
+		{"5/15 * * * *", "at minutes 5, 20, 35 and 50 of every hour", "每小时的第5、20、35和50分钟"},
+		{"0 0-5,12 * * *", "at minute 0 during hours 0 through 5 and 12", "0至5和12点第0分钟"},
+		{"0 */2 * * *", "at minute 0 every 2 hours", "每2小时第0分钟"},
+		{"0 30 9,17 * * *", "at 09:30 and 17:30", "09:30和17:30"},
+		{"5 0 */6 * * *",
+			"at 00:00:05, 06:00:05, 12:00:05 and 18:00:05",
+			"00:00:05、06:00:05、12:00:05和18:00:05"},
+
+		// Days, months and time zones
+		{"0 0 13 * fri",
+			"at 00:00, on day 13 of the month or Friday",
+			"每月13日或周五，00:00"},
+		{"0 0 1,15 jan-mar,dec *",
+			"at 00:00, on days 1 and 15 of the month, in January through March and December",
+			"1月至3月和12月，每月1和15日，00:00"},
+		{"0 8 * * fri-mon", "at 08:00, Friday through Monday", "周五至周一，08:00"},
+		{"0 8 * * sat,sun", "at 08:00, Saturday and Sunday", "周六和周日，08:00"},
+		{"CRON_TZ=Asia/Tokyo 30 4 * * *", "at 04:30 (Asia/Tokyo time)", "Asia/Tokyo时间，04:30"},
+
+		// Quartz modifiers
+		{"0 0 L * *", "at 00:00, on the last day of the month", "每月最后一天，00:00"},
+		{"0 0 L-2 * *", "at 00:00, on the last day of the month minus 2", "每月最后一天的前2天，00:00"},
+		{"0 0 ? * 5L", "at 00:00, on the last Friday of the month", "每月最后一个周五，00:00"},
+		{"0 0 ? * mon#2", "at 00:00, on the second Monday of the month", "每月第二个周一，00:00"},
+		{"0 0 15W,LW * *",
+			"at 00:00, on the last weekday of the month or on the weekday nearest day 15 of the month",
+			"每月最后一个工作日或每月离15日最近的工作日，00:00"},
+
+		// Descriptors
+		{"@hourly", "at minute 0 of every hour", "每小时的第0分钟"},
+		{"@daily", "at 00:00", "00:00"},
+		{"@weekly", "at 00:00, Sunday", "周日，00:00"},
+		{"@monthly", "at 00:00, on day 1 of the month", "每月1日，00:00"},
+		{"@yearly", "at 00:00, on day 1 of the month, in January", "1月，每月1日，00:00"},
+		{"@every 1h30m", "every 1 hour and 30 minutes", "每1小时30分钟"},
+		{"@every 5s", "every 5 seconds", "每5秒"},
+		{"@every 1500ms", "every 1 second and 500 milliseconds", "每1秒500毫秒"},
+		{"@every 250ms", "every 250 milliseconds", "每250毫秒"},
+	}
+
+	chinese := NewDescriber(Chinese)
+	for _, c := range tests {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.spec, err)
+			continue
+		}
+		if actual, err := Describe(sched); err != nil || actual != c.english {
+			t.Errorf("%s => expected %q, got %q (%v)", c.spec, c.english, actual, err)
+		}
+		if actual, err := chinese.Describe(sched); err != nil || actual != c.chinese {
+			t.Errorf("%s => expected %q, got %q (%v)", c.spec, c.chinese, actual, err)
+		}
+	}
+}
+
+func TestDescribeDomAndDow(t *testing.T) {
+	sched, err := NewParser(Minute | Hour | Dom | Month | Dow | DayAnd).Parse("0 0 13 * fri")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		english = "at 00:00, on day 13 of the month, only if it is Friday"
+		chinese = "每月13日，且为周五，00:00"
+	)
+	if actual, _ := Describe(sched); actual != english {
+		t.Errorf("expected %q, got %q", english, actual)
+	}
+	if actual, _ := NewDescriber(Chinese).Describe(sched); actual != chinese {
+		t.Errorf("expected %q, got %q", chinese, actual)
+	}
+}
+
+func TestDescribeYears(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | YearOptional)
+	sched, err := parser.Parse("0 0 1 1 * 2027-2029")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = "at 00:00, on day 1 of the month, in January, in 2027 through 2029"
+	if actual, _ := Describe(sched); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestDescribeUnsupported(t *testing.T) {
+	if _, err := Describe(new(ZeroSchedule)); err == nil {
+		t.Error("expected an error describing an unsupported schedule")
+	}
+}
+
+type catalogOverride struct {
+	Catalog
+	every string
+}
+
+func (c catalogOverride) Message(id Message) string {
+	if id == MsgEvery {
+		return c.every
+	}
+	return c.Catalog.Message(id)
+}
+
+func TestDescribeCustomCatalog(t *testing.T) {
+	describer := NewDescriber(catalogOverride{English, "once every %s"})
+	actual, err := describer.Describe(Every(10 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "once every 10 minutes"; actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ScheduleFactory为用户定义的描述符创建时间表。
+// loc是spec中CRON_TZ=或TZ=前缀指定的时区，没有前缀时为time.Local。
+type ScheduleFactory func(loc *time.Location) (Schedule, error)
+
+// Descriptors是一组用户定义的描述符，例如"@business-hours"，通过Parser.WithDescriptors启用。
+// 描述符可以定义为spec（其中可以再使用其他描述符），也可以定义为创建时间表的函数。
+// 用户定义的描述符优先于同名的内置描述符。
+// Descriptors可以被多个解析器共享，但在使用过程中不应再修改。
+type Descriptors struct {
+	specs     map[string]string
+	factories map[string]ScheduleFactory
+}
+
+// NewDescriptors返回一个空的Descriptors。
+func NewDescriptors() *Descriptors {
+	return &Descriptors{
+		specs:     make(map[string]string),
+		factories: make(map[string]ScheduleFactory),
+	}
+}
+
+// Define将描述符name定义为spec，替换name原有的定义。
+// spec由使用该描述符的解析器解析，可以包括CRON_TZ=前缀、H表达式和其他描述符；
+// 没有时区前缀时使用引用处的时区。循环的定义在解析时报告为ReasonDescriptorCycle。
+// 如果name不以@开头或包含空白，它会恐慌。
+func (d *Descriptors) Define(name, spec string) {
+	checkDescriptorName(name)
+	delete(d.factories, name)
+	d.specs[name] = spec
+}
+
+// DefineFunc将描述符name定义为由factory创建的时间表，替换name原有的定义。
+// 如果name不以@开头或包含空白，它会恐慌。
+func (d *Descriptors) DefineFunc(name string, factory ScheduleFactory) {
+	checkDescriptorName(name)
+	delete(d.specs, name)
+	d.factories[name] = factory
+}
+
+// defines返回name是否为用户定义的描述符。nil的*Descriptors不定义任何描述符。
+func (d *Descriptors) defines(name string) bool {
+	if d == nil {
+		return false
+	}
+	_, spec := d.specs[name]
+	_, factory := d.factories[name]
+	return spec || factory
+}
+
+// checkDescriptorName在name不是有效的描述符名称时恐慌。
+func checkDescriptorName(name string) {
+	if len(name) < 2 || name[0] != '@' || strings.IndexFunc(name, unicode.IsSpace) >= 0 {
+		panic(fmt.Sprintf("invalid descriptor name %q: must start with @ and contain no spaces", name))
+	}
+}
+
+// parseUserDescriptor解析用户定义的描述符name。expanding是正在展开的描述符，用于检测循环。
+// 定义中的错误都定位到name上，因为定义并不在被解析的spec中。
+func (p Parser) parseUserDescriptor(name string, loc *time.Location, key string, hashed bool, expanding []string) (Schedule, error) {
+	if factory, ok := p.descriptors.factories[name]; ok {
+		schedule, err := factory(loc)
+		if err != nil {
+			return nil, atToken(parseErrorf(ReasonSyntax, "descriptor %s: %v", name, err), name, 0)
+		}
+		return schedule, nil
+	}
+
+	for i, other := range expanding {
+		if other == name {
+			chain := append(append([]string(nil), expanding[i:]...), name)
+			return nil, atToken(parseErrorf(ReasonDescriptorCycle, "descriptor cycle: %s", strings.Join(chain, " -> ")), name, 0)
+		}
+	}
+
+	spec := p.descriptors.specs[name]
+	if loc != time.Local && !strings.HasPrefix(spec, "TZ=") && !strings.HasPrefix(spec, "CRON_TZ=") {
+		spec = "CRON_TZ=" + loc.String() + " " + spec
+	}
+	schedule, err := p.parseSpec(spec, key, hashed, append(expanding[:len(expanding):len(expanding)], name))
+	if perr, ok := err.(*ParseError); ok {
+		msg := perr.Msg
+		if perr.Reason != ReasonDescriptorCycle {
+			msg = fmt.Sprintf("descriptor %s: %v", name, perr)
+		}
+		return nil, atToken(parseErrorf(perr.Reason, "%s", msg), name, 0)
+	}
+	return schedule, err
+}
@@ -0,0 +1,148 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUserDescriptors(t *testing.T) {
+	d := NewDescriptors()
+	d.Define("@business-hours", "0 9-17 * * mon-fri")
+	d.Define("@quarterly", "0 0 1 jan,apr,jul,oct *")
+	d.Define("@nightly-batch", "CRON_TZ=UTC 30 2 * * *")
+	d.Define("@weekday-mornings", "@morning")
+	d.Define("@morning", "0 8 * * 1-5")
+	d.Define("@daily", "0 3 * * *")
+	d.DefineFunc("@fortnightly", func(loc *time.Location) (Schedule, error) {
+		return EveryFrom(14*24*time.Hour, time.Date(2012, time.January, 2, 0, 0, 0, 0, loc)), nil
+	})
+	parser := standardParser.WithDescriptors(d)
+
+	tests := []struct {
+		spec     string
+		time     string
+		expected string
+	}{
+		{"@business-hours", "Sat Jul 7 12:00 2012", "Mon Jul 9 09:00 2012"},
+		{"@business-hours", "Mon Jul 9 17:00 2012", "Tue Jul 10 09:00 2012"},
+		{"@quarterly", "Mon Jul 9 12:00 2012", "Mon Oct 1 00:00 2012"},
+		{"@nightly-batch", "2012-07-09T12:00:00-0000", "2012-07-10T02:30:00-0000"},
+		{"@weekday-mornings", "Sat Jul 7 12:00 2012", "Mon Jul 9 08:00 2012"},
+		{"@fortnightly", "Mon Jul 9 12:00 2012", "Mon Jul 16 00:00 2012"},
+
+		// User-defined descriptors take precedence over built-in ones.
+		{"@daily", "Mon Jul 9 12:00 2012", "Tue Jul 10 03:00 2012"},
+		{"@hourly", "Mon Jul 9 12:00 2012", "Mon Jul 9 13:00 2012"},
+
+		// The time zone of the referencing spec applies unless the definition has its own.
+		{"CRON_TZ=America/New_York @business-hours", "2012-07-09T12:00:00-0000", "2012-07-09T13:00:00-0000"},
+		{"CRON_TZ=America/New_York @nightly-batch", "2012-07-09T12:00:00-0000", "2012-07-10T02:30:00-0000"},
+		{"CRON_TZ=Asia/Tokyo @fortnightly", "2012-07-09T12:00:00-0000", "2012-07-15T15:00:00-0000"},
+	}
+	for _, test := range tests {
+		sched, err := parser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		actual := sched.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+	}
+
+	// Without the registry, only built-in descriptors are recognised.
+	if _, err := standardParser.Parse("@business-hours"); err == nil {
+		t.Error("expected an error parsing a user-defined descriptor without the registry")
+	}
+}
+
+func TestUserDescriptorsWithKey(t *testing.T) {
+	d := NewDescriptors()
+	d.Define("@nightly", "H H(0-5) * * *")
+	parser := standardParser.WithDescriptors(d)
+
+	a, err := parser.ParseWithKey("@nightly", "job-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := parser.ParseWithKey("@nightly", "job-a")
+	expected, _ := parser.ParseWithKey("H H(0-5) * * *", "job-a")
+	if !equalSpecSchedules(a.(*SpecSchedule), b.(*SpecSchedule)) || !equalSpecSchedules(a.(*SpecSchedule), expected.(*SpecSchedule)) {
+		t.Errorf("expected the descriptor to hash like its definition, got %v and %v", a, expected)
+	}
+	if _, err := parser.Parse("@nightly"); err == nil {
+		t.Error("expected an error using H without a key")
+	}
+}
+
+func TestUserDescriptorErrors(t *testing.T) {
+	d := NewDescriptors()
+	d.Define("@self", "@self")
+	d.Define("@a", "@b")
+	d.Define("@b", "@c")
+	d.Define("@c", "@a")
+	d.Define("@uses-cycle", "@a")
+	d.Define("@broken", "0 25 * * *")
+	d.Define("@nested-broken", "@broken")
+	d.DefineFunc("@failing", func(*time.Location) (Schedule, error) {
+		return nil, errors.New("backend unavailable")
+	})
+	parser := standardParser.WithDescriptors(d)
+
+	tests := []struct {
+		spec   string
+		reason ParseErrorReason
+		token  string
+		offset int
+		msg    string
+	}{
+		{"@self", ReasonDescriptorCycle, "@self", 0, "descriptor cycle: @self -> @self"},
+		{"@a", ReasonDescriptorCycle, "@a", 0, "descriptor cycle: @a -> @b -> @c -> @a"},
+		{"CRON_TZ=UTC @uses-cycle", ReasonDescriptorCycle, "@uses-cycle", 12, "descriptor cycle: @a -> @b -> @c -> @a"},
+		{"@broken", ReasonOutOfRange, "@broken", 0, "descriptor @broken: hour field"},
+		{"@nested-broken", ReasonOutOfRange, "@nested-broken", 0, "descriptor @nested-broken: descriptor @broken: hour field"},
+		{"@failing", ReasonSyntax, "@failing", 0, "descriptor @failing: backend unavailable"},
+		{"@unknown", ReasonUnknownDescriptor, "@unknown", 0, "unrecognized descriptor"},
+	}
+	for _, test := range tests {
+		_, err := parser.Parse(test.spec)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%s => expected a *ParseError, got %v", test.spec, err)
+			continue
+		}
+		if perr.Reason != test.reason || perr.Token != test.token || perr.Offset != test.offset || !strings.HasPrefix(perr.Msg, test.msg) {
+			t.Errorf("%s => expected %v %q at %d (%q), got %v %q at %d (%q)", test.spec,
+				test.reason, test.token, test.offset, test.msg,
+				perr.Reason, perr.Token, perr.Offset, perr.Msg)
+		}
+		if perr.Spec != test.spec {
+			t.Errorf("%s => expected the spec on the error, got %q", test.spec, perr.Spec)
+		}
+	}
+}
+
+func TestDescriptorNames(t *testing.T) {
+	d := NewDescriptors()
+	for _, name := range []string{"", "@", "nightly", "@two words"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: expected a panic", name)
+				}
+			}()
+			d.Define(name, "* * * * *")
+		}()
+	}
+
+	// Redefining a name replaces the previous definition of either kind.
+	d.DefineFunc("@x", func(*time.Location) (Schedule, error) { return Every(time.Hour), nil })
+	d.Define("@x", "0 0 * * *")
+	sched, err := standardParser.WithDescriptors(d).Parse("@x")
+	if _, ok := sched.(*SpecSchedule); err != nil || !ok {
+		t.Errorf("expected the spec definition to replace the function, got %v (%v)", sched, err)
+	}
+}
@@ -0,0 +1,271 @@
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 各个方言在NewParser之上使用的选项。
+var (
+	eventBridgeParser = NewParser(Minute | Hour | Dom | Month | Dow | Year | QuartzModifiers)
+	kubernetesParser  = NewParser(Minute | Hour | Dom | Month | Dow | Descriptor)
+)
+
+// EventBridge的星期和年份范围：星期从1（星期日）开始，年份比Year选项的范围更大。
+var (
+	eventBridgeDow = bounds{1, 7, map[string]uint{
+		"sun": 1,
+		"mon": 2,
+		"tue": 3,
+		"wed": 4,
+		"thu": 5,
+		"fri": 6,
+		"sat": 7,
+	}}
+	eventBridgeYears = bounds{1970, 2199, nil}
+)
+
+// dialectField是方言表达式中的一个字段：它在places中的位置和取值范围。
+type dialectField struct {
+	place  int
+	bounds bounds
+}
+
+// 各个方言的字段，按在表达式中出现的顺序排列。
+var (
+	eventBridgeFields = []dialectField{{1, minutes}, {2, hours}, {3, dom}, {4, months}, {5, eventBridgeDow}, {6, eventBridgeYears}}
+	kubernetesFields  = []dialectField{{1, minutes}, {2, hours}, {3, dom}, {4, months}, {5, dow}}
+)
+
+// EventBridgeParser只接受AWS EventBridge的调度表达式：
+//   cron(minutes hours day-of-month month day-of-week year)
+//   rate(value unit)
+// cron()的六个字段都是必需的，日期和星期中必须恰好有一个为"?"，其他字段不能使用"?"；
+// 星期的数值为1-7（1为星期日），也可以使用SUN-SAT，单独的"L"表示星期六；年份为1970-2199。
+// 范围的起点不能大于终点。时间表按UTC计算。
+// rate()的单位为minute(s)、hour(s)或day(s)，值为1时必须使用单数。
+//
+// EventBridge不接受的写法（例如秒、描述符或TZ=前缀）会返回Reason为ReasonDialect的*ParseError，
+// 因此可以在部署之前发现不兼容的表达式。
+type EventBridgeParser struct{}
+
+// Parse返回代表给定EventBridge表达式的时间表。返回的错误都是*ParseError。
+func (EventBridgeParser) Parse(spec string) (Schedule, error) {
+	schedule, err := parseEventBridge(spec)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = spec
+	}
+	return schedule, err
+}
+
+// KubernetesParser只接受Kubernetes CronJob的.spec.schedule：
+// 五个字段的标准crontab表达式（"?"只能用于日期和星期，范围不能回绕），以及@yearly、@annually、@monthly、@weekly、@daily、
+// @midnight和@hourly。Kubernetes不接受的"@every"、TZ=或CRON_TZ=前缀（应使用.spec.timeZone）、
+// 重复规则和重复时间间隔会返回Reason为ReasonDialect的*ParseError。
+type KubernetesParser struct{}
+
+// Parse返回代表给定CronJob调度表达式的时间表。返回的错误都是*ParseError。
+func (KubernetesParser) Parse(spec string) (Schedule, error) {
+	schedule, err := parseKubernetes(spec)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = spec
+	}
+	return schedule, err
+}
+
+// dialectErrorf返回定位到token上的ReasonDialect错误。
+func dialectErrorf(token string, offset int, format string, args ...interface{}) error {
+	return atToken(parseErrorf(ReasonDialect, format, args...), token, offset)
+}
+
+// parseEventBridge实现了EventBridgeParser.Parse。
+func parseEventBridge(spec string) (Schedule, error) {
+	var parse func(string) (Schedule, error)
+	switch {
+	case strings.HasPrefix(spec, "cron(") && strings.HasSuffix(spec, ")"):
+		parse = parseEventBridgeCron
+	case strings.HasPrefix(spec, "rate(") && strings.HasSuffix(spec, ")"):
+		parse = parseEventBridgeRate
+	default:
+		return nil, dialectErrorf(spec, 0, "EventBridge expressions must be cron(...) or rate(...): %s", spec)
+	}
+	schedule, err := parse(spec[5 : len(spec)-1])
+	if err != nil {
+		return nil, shiftError(err, 5)
+	}
+	return schedule, nil
+}
+
+// parseEventBridgeCron解析cron()括号中的六个字段。
+func parseEventBridgeCron(expr string) (Schedule, error) {
+	fields, offsets := splitFields(expr)
+	if len(fields) != 6 {
+		return nil, dialectErrorf(expr, 0, "EventBridge cron expressions have 6 fields (minutes hours day-of-month month day-of-week year), found %d: %s", len(fields), expr)
+	}
+
+	var (
+		domField, dowField = fields[2], fields[4]
+		domAny, dowAny     = domField == "?", dowField == "?"
+	)
+	switch {
+	case domAny && dowAny:
+		return nil, dialectErrorf(dowField, offsets[4], "EventBridge allows ? in only one of day-of-month and day-of-week: %s", expr)
+	case !domAny && !dowAny:
+		return nil, dialectErrorf(dowField, offsets[4], "EventBridge requires ? in either day-of-month or day-of-week: %s", expr)
+	}
+
+	if err := checkDialectFields("EventBridge", eventBridgeFields, fields, offsets); err != nil {
+		return nil, err
+	}
+
+	// EventBridge的星期从1（星期日）开始，转换为从0开始后长度不变，因此偏移仍然有效。
+	shifted, err := shiftWeekdays(dowField)
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Field = fieldNames[5]
+		}
+		return nil, shiftError(err, offsets[4])
+	}
+	// 年份由这里按EventBridge的范围解析，其余字段交给eventBridgeParser。
+	years, star, err := getValues(fields[5], eventBridgeYears)
+	if err != nil {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Field = fieldNames[6]
+		}
+		return nil, shiftError(err, offsets[5])
+	}
+	expr = expr[:offsets[4]] + shifted + expr[offsets[4]+len(dowField):offsets[5]] + "*"
+
+	schedule, err := eventBridgeParser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	s := schedule.(*SpecSchedule)
+	s.Location = time.UTC
+	if !star {
+		s.Year = years
+	}
+	return s, nil
+}
+
+// checkDialectFields拒绝NewParser接受、而方言不接受的写法：回绕的范围（例如"22-2"），
+// 以及日期和星期以外的"?"。
+func checkDialectFields(dialect string, layout []dialectField, fields []string, offsets []int) error {
+	for i, f := range layout {
+		err := forEachRange(fields[i], func(expr string) error {
+			if expr == "?" {
+				if f.place != 3 && f.place != 5 {
+					return parseErrorf(ReasonDialect, "%s allows ? only in day-of-month and day-of-week: %s", dialect, expr)
+				}
+				return nil
+			}
+			lowAndHigh := strings.Split(strings.Split(expr, "/")[0], "-")
+			if len(lowAndHigh) != 2 {
+				return nil
+			}
+			start, err := parseIntOrName(lowAndHigh[0], f.bounds.names)
+			if err != nil {
+				return nil
+			}
+			end, err := parseIntOrName(lowAndHigh[1], f.bounds.names)
+			if err == nil && start > end {
+				return parseErrorf(ReasonDialect, "%s does not support ranges that wrap around: %s", dialect, expr)
+			}
+			return nil
+		})
+		if err != nil {
+			if perr, ok := err.(*ParseError); ok {
+				perr.Field = fieldNames[f.place]
+			}
+			return shiftError(err, offsets[i])
+		}
+	}
+	return nil
+}
+
+// shiftWeekdays将EventBridge中从1（星期日）开始的星期数值转换为从0开始的数值。
+// 步长（"/"之后）和序号（"#"之后）保持不变。
+func shiftWeekdays(field string) (string, error) {
+	b := []byte(field)
+	for i := 0; i < len(b); i++ {
+		if b[i] < '0' || b[i] > '9' {
+			continue
+		}
+		j := i
+		for j < len(b) && b[j] >= '0' && b[j] <= '9' {
+			j++
+		}
+		if i == 0 || b[i-1] != '/' && b[i-1] != '#' {
+			if j-i != 1 || b[i] < '1' || b[i] > '7' {
+				return "", atToken(parseErrorf(ReasonOutOfRange, "EventBridge day-of-week values are 1-7 (SUN-SAT): %s", field[i:j]), field[i:j], i)
+			}
+			b[i]--
+		}
+		i = j
+	}
+	return string(b), nil
+}
+
+// parseEventBridgeRate解析rate()括号中的"value unit"。
+func parseEventBridgeRate(expr string) (Schedule, error) {
+	fields, offsets := splitFields(expr)
+	if len(fields) != 2 {
+		return nil, dialectErrorf(expr, 0, "EventBridge rate expressions must be rate(value unit): %s", expr)
+	}
+	value, err := strconv.Atoi(fields[0])
+	if err != nil || value < 1 {
+		return nil, atToken(parseErrorf(ReasonOutOfRange, "EventBridge rate value must be a positive integer: %s", fields[0]), fields[0], offsets[0])
+	}
+
+	var unit time.Duration
+	switch strings.TrimSuffix(fields[1], "s") {
+	case "minute":
+		unit = time.Minute
+	case "hour":
+		unit = time.Hour
+	case "day":
+		unit = 24 * time.Hour
+	default:
+		return nil, dialectErrorf(fields[1], offsets[1], "EventBridge rate unit must be minute(s), hour(s) or day(s): %s", fields[1])
+	}
+	if plural := strings.HasSuffix(fields[1], "s"); plural != (value > 1) {
+		return nil, dialectErrorf(fields[1], offsets[1], "EventBridge requires a singular unit for 1 and a plural unit otherwise: %s", expr)
+	}
+	return Every(time.Duration(value) * unit), nil
+}
+
+// kubernetesDescriptors是Kubernetes CronJob接受的描述符。
+var kubernetesDescriptors = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// parseKubernetes实现了KubernetesParser.Parse。
+func parseKubernetes(spec string) (Schedule, error) {
+	switch {
+	case strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ="):
+		prefix := spec
+		if i := strings.Index(spec, " "); i >= 0 {
+			prefix = spec[:i]
+		}
+		return nil, dialectErrorf(prefix, 0, "Kubernetes does not support %s in the schedule, set .spec.timeZone instead", prefix)
+	case strings.HasPrefix(spec, "@every"):
+		return nil, dialectErrorf(spec, 0, "Kubernetes does not support @every: %s", spec)
+	case strings.HasPrefix(spec, "@") && !kubernetesDescriptors[spec]:
+		return nil, dialectErrorf(spec, 0, "Kubernetes does not support the descriptor %s", spec)
+	case isRRule(spec) || isRepeatingInterval(spec):
+		return nil, dialectErrorf(spec, 0, "Kubernetes only supports 5-field cron expressions: %s", spec)
+	}
+	if fields, offsets := splitFields(spec); len(fields) == len(kubernetesFields) {
+		if err := checkDialectFields("Kubernetes", kubernetesFields, fields, offsets); err != nil {
+			return nil, err
+		}
+	}
+	return kubernetesParser.Parse(spec)
+}
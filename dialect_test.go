@@ -0,0 +1,138 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventBridgeParser(t *testing.T) {
+	tests := []struct {
+		spec     string
+		time     string
+		expected string
+	}{
+		{"cron(0 12 * * ? *)", "2012-07-09T13:00:00-0000", "2012-07-10T12:00:00-0000"},
+		{"cron(0 18 ? * MON-FRI *)", "2012-07-07T00:00:00-0000", "2012-07-09T18:00:00-0000"},
+		{"cron(0 8 1 * ? *)", "2012-07-09T00:00:00-0000", "2012-08-01T08:00:00-0000"},
+		{"cron(0/10 * ? * 1,7 *)", "2012-07-09T00:00:00-0000", "2012-07-14T00:00:00-0000"},
+		{"cron(0 9 ? * 2#1 *)", "2012-07-09T10:00:00-0000", "2012-08-06T09:00:00-0000"},
+		{"cron(15 10 ? * 6L 2022-2023)", "2022-01-01T00:00:00-0000", "2022-01-28T10:15:00-0000"},
+		{"cron(15 10 ? * 6L 2022-2023)", "2023-12-29T10:15:00-0000", ""},
+		{"cron(0 12 ? * L *)", "2012-07-09T00:00:00-0000", "2012-07-14T12:00:00-0000"},
+		{"cron(0 12 1 1 ? 2150)", "2012-07-09T00:00:00-0000", "2150-01-01T12:00:00-0000"},
+		{"cron(0 12 1 1 ? 2198/2)", "2199-01-01T12:00:00-0000", ""},
+		{"cron(0 12 1 1 ? 2199)", "2198-07-09T00:00:00-0000", "2199-01-01T12:00:00-0000"},
+
+		// Schedules are evaluated in UTC.
+		{"cron(0 12 * * ? *)", "TZ=America/New_York 2012-07-09T09:00:00-0400", "2012-07-10T12:00:00-0000"},
+	}
+	for _, test := range tests {
+		schedule, err := EventBridgeParser{}.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		actual := schedule.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+	}
+}
+
+func TestEventBridgeRate(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected time.Duration
+	}{
+		{"rate(1 minute)", time.Minute},
+		{"rate(5 minutes)", 5 * time.Minute},
+		{"rate(1 hour)", time.Hour},
+		{"rate(12 hours)", 12 * time.Hour},
+		{"rate(1 day)", 24 * time.Hour},
+	}
+	for _, test := range tests {
+		schedule, err := EventBridgeParser{}.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		if actual, ok := schedule.(ConstantDelaySchedule); !ok || actual.Delay != test.expected {
+			t.Errorf("%s => expected every %v, got %#v", test.spec, test.expected, schedule)
+		}
+	}
+}
+
+func TestDialectErrors(t *testing.T) {
+	var (
+		eventBridge = EventBridgeParser{}
+		kubernetes  = KubernetesParser{}
+	)
+	tests := []struct {
+		parser ScheduleParser
+		spec   string
+		reason ParseErrorReason
+		field  string
+		token  string
+		offset int
+	}{
+		{eventBridge, "0 12 * * ? *", ReasonDialect, "", "0 12 * * ? *", 0},
+		{eventBridge, "cron(0 12 * * ?)", ReasonDialect, "", "0 12 * * ?", 5},
+		{eventBridge, "cron(0 0 12 * * ? *)", ReasonDialect, "", "0 0 12 * * ? *", 5},
+		{eventBridge, "cron(0 12 * * * *)", ReasonDialect, "", "*", 14},
+		{eventBridge, "cron(0 12 ? * ? *)", ReasonDialect, "", "?", 14},
+		{eventBridge, "cron(0 12 ? * 8 *)", ReasonOutOfRange, "day of week", "8", 14},
+		{eventBridge, "cron(0 12 ? * 2,0 *)", ReasonOutOfRange, "day of week", "0", 16},
+		{eventBridge, "cron(0 25 ? * 1 *)", ReasonOutOfRange, "hour", "25", 7},
+		{eventBridge, "cron(0 12 ? * 1 1969)", ReasonOutOfRange, "year", "1969", 16},
+		{eventBridge, "cron(0 12 ? * 1 2200)", ReasonOutOfRange, "year", "2200", 16},
+		{eventBridge, "cron(? 12 ? * MON *)", ReasonDialect, "minute", "?", 5},
+		{eventBridge, "cron(0 12 ? ? MON *)", ReasonDialect, "month", "?", 12},
+		{eventBridge, "cron(0 12 ? * MON ?)", ReasonDialect, "year", "?", 18},
+		{eventBridge, "cron(0 22-2 ? * MON *)", ReasonDialect, "hour", "22-2", 7},
+		{eventBridge, "cron(0 12 ? * FRI-MON *)", ReasonDialect, "day of week", "FRI-MON", 14},
+		{eventBridge, "cron(0 12 ? * 1,7-2/2 *)", ReasonDialect, "day of week", "7-2/2", 16},
+		{eventBridge, "cron(0 12 ? * 1 2030-2025)", ReasonDialect, "year", "2030-2025", 16},
+		{eventBridge, "rate(5m)", ReasonDialect, "", "5m", 5},
+		{eventBridge, "rate(0 minutes)", ReasonOutOfRange, "", "0", 5},
+		{eventBridge, "rate(1 minutes)", ReasonDialect, "", "minutes", 7},
+		{eventBridge, "rate(5 minute)", ReasonDialect, "", "minute", 7},
+		{eventBridge, "rate(5 weeks)", ReasonDialect, "", "weeks", 7},
+
+		{kubernetes, "@every 1h", ReasonDialect, "", "@every 1h", 0},
+		{kubernetes, "CRON_TZ=UTC 0 0 * * *", ReasonDialect, "", "CRON_TZ=UTC", 0},
+		{kubernetes, "TZ=UTC 0 0 * * *", ReasonDialect, "", "TZ=UTC", 0},
+		{kubernetes, "@businessday 1", ReasonDialect, "", "@businessday 1", 0},
+		{kubernetes, "R/2026-01-01T00:00:00Z/PT1H", ReasonDialect, "", "R/2026-01-01T00:00:00Z/PT1H", 0},
+		{kubernetes, "RRULE:FREQ=DAILY", ReasonDialect, "", "RRULE:FREQ=DAILY", 0},
+		{kubernetes, "0 0 0 * * *", ReasonFieldCount, "", "0 0 0 * * *", 0},
+		{kubernetes, "0 0 * * 7#1", ReasonSyntax, "day of week", "7#1", 8},
+		{kubernetes, "0 22-2 * * *", ReasonDialect, "hour", "22-2", 2},
+		{kubernetes, "? * * * *", ReasonDialect, "minute", "?", 0},
+		{kubernetes, "0 0 * * fri-mon", ReasonDialect, "day of week", "fri-mon", 8},
+		{kubernetes, "0 0 * dec-jan *", ReasonDialect, "month", "dec-jan", 6},
+	}
+	for _, test := range tests {
+		_, err := test.parser.Parse(test.spec)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q => expected a *ParseError, got %v", test.spec, err)
+			continue
+		}
+		if perr.Reason != test.reason || perr.Field != test.field || perr.Token != test.token || perr.Offset != test.offset {
+			t.Errorf("%q => expected %v %q %q at %d, got %v %q %q at %d (%v)", test.spec,
+				test.reason, test.field, test.token, test.offset,
+				perr.Reason, perr.Field, perr.Token, perr.Offset, perr)
+		}
+		if perr.Spec != test.spec {
+			t.Errorf("%q => expected the spec on the error, got %q", test.spec, perr.Spec)
+		}
+	}
+}
+
+func TestKubernetesParser(t *testing.T) {
+	for _, spec := range []string{"*/5 * * * *", "0 0 * * 1-5", "0 0 1 jan ?", "0 0 ? * mon-fri", "0 9-17/2 * * *", "@daily", "@midnight", "@hourly"} {
+		if _, err := (KubernetesParser{}).Parse(spec); err != nil {
+			t.Errorf("%s => unexpected error %v", spec, err)
+		}
+	}
+}
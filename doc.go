@@ -73,6 +73,15 @@ parser you saw earlier, except that its seconds field is REQUIRED:
 That emulates Quartz, the most popular alternative Cron schedule format:
 http://www.quartz-scheduler.org/documentation/quartz-2.x/tutorials/crontrigger.html
 
+A trailing year field (1970-2099) may be enabled with the Year or YearOptional
+options.  It accepts the same "*", "/", "," and "-" expressions as the other
+fields, and can be used to express one-off or bounded schedules:
+
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.YearOptional)
+	sched, err := p.Parse("0 0 1 1 * 2027-2029")
+
+Once the last year of such a schedule has passed, Next returns the zero time.
+
 Special Characters
 
 Asterisk ( * )
@@ -100,11 +109,67 @@ Hyphen ( - )
 Hyphens are used to define ranges. For example, 9-17 would indicate every
 hour between 9am and 5pm inclusive.
 
+A range whose beginning is greater than its end wraps around the end of the
+field.  For example, 22-2 in the hours field means 10pm through 2am, FRI-MON in
+the day-of-week field means Friday through Monday, and 22-4/2 means 10pm,
+midnight, 2am and 4am.
+
 Question mark ( ? )
 
 Question mark may be used instead of '*' for leaving either day-of-month or
 day-of-week blank.
 
+Day-of-month and day-of-week
+
+Following Vixie cron, a schedule that restricts both the day-of-month and the
+day-of-week fields fires on days matching either of them, so "0 0 13 * fri"
+runs on every 13th and on every Friday.  If either field is '*' or '?', only
+the other one applies.  Parsers created with the DayAnd option require both
+fields to match instead, so the same spec runs only on Friday the 13th.  The
+DayQuartz option follows Quartz and rejects specs unless exactly one of the two
+fields is '?':
+
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.DayAnd)
+	sched, err := p.Parse("0 0 13 * fri") // Friday the 13th
+
+Quartz modifiers ( L W # )
+
+Parsers created with the QuartzModifiers option additionally accept the
+following day-of-month and day-of-week expressions, as found in Quartz:
+
+	Expression | Field        | Meaning
+	---------- | -----        | -------
+	L          | Day of month | Last day of the month
+	L-3        | Day of month | Third to last day of the month
+	15W        | Day of month | Weekday (Mon-Fri) nearest to the 15th
+	LW         | Day of month | Last weekday of the month
+	L          | Day of week  | Saturday, the last day of the week
+	5L, FRIL   | Day of week  | Last Friday of the month
+	MON#2      | Day of week  | Second Monday of the month
+
+The nearest weekday never crosses a month boundary: "1W" on a month that starts
+on a Saturday fires on Monday the 3rd.  Day-of-week values keep this package's
+numbering (0-6, starting at Sunday), so "5L" is Friday rather than Thursday as
+in Quartz.  Modifiers may be mixed with plain values in a list, e.g. "1,L".
+
+Hash ( H )
+
+Schedules parsed with Parser.ParseWithKey may use Jenkins-style hash
+expressions to spread many similar jobs over time.  "H" stands for a single
+value of the field chosen by hashing the key, "H(a-b)" restricts that value to
+the given range, and "H/n" (or "H(a-b)/n") picks a hashed starting point and
+then repeats every n units.  Like plain ranges, "H(22-2)" wraps around and
+picks an hour between 10pm and 2am.  The same key always yields the same
+schedule:
+
+	// Once a day at a minute and hour (between midnight and 7am) derived from the job name.
+	sched, err := cron.ParseStandard("H H(0-7) * * *") // error: requires a key
+	sched, err = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).
+		ParseWithKey("H H(0-7) * * *", "nightly-report")
+
+Note that "H" in the day-of-month field may select the 29th to 31st, which do
+not exist in every month; use "H(1-28)" to run every month.
+
 Predefined schedules
 
 You may use one of several pre-defined schedules in place of a cron expression.
@@ -116,6 +181,26 @@ You may use one of several pre-defined schedules in place of a cron expression.
 	@weekly                | Run once a week, midnight between Sat/Sun  | 0 0 * * 0
 	@daily (or @midnight)  | Run once a day, midnight                   | 0 0 * * *
 	@hourly                | Run once an hour, beginning of hour        | 0 * * * *
+	@reboot                | Run once, when the Cron is started         |
+
+An @reboot entry runs once at the first Start of the Cron, or immediately if it
+is added to a Cron that is already running.  Schedule a RebootSchedule with
+EveryStart set to also run it again on each Start that follows a Stop:
+
+	c.Schedule(cron.RebootSchedule{EveryStart: true}, cron.FuncJob(warmCaches))
+
+Site-specific descriptors may be defined once in a Descriptors registry and
+used by any parser created with WithDescriptors.  A descriptor is defined either
+as a spec, which may itself use other descriptors, or as a function returning a
+Schedule.  Definitions that refer back to themselves are reported as parse
+errors with the reason ReasonDescriptorCycle:
+
+	d := cron.NewDescriptors()
+	d.Define("@business-hours", "0 9-17 * * mon-fri")
+	d.Define("@quarterly", "0 0 1 jan,apr,jul,oct *")
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor).WithDescriptors(d)
+	c := cron.New(cron.WithParser(p))
+	c.AddFunc("@business-hours", func() { fmt.Println("Every hour during business hours") })
 
 Intervals
 
@@ -134,6 +219,35 @@ Note: The interval does not take the job runtime into account.  For example,
 if a job takes 3 minutes to run, and it is scheduled to run every 5 minutes,
 it will have only 2 minutes of idle time between each run.
 
+Because the interval starts when the job is added, "@every" schedules drift
+against the wall clock and across restarts.  An anchor puts the runs on a fixed
+grid instead:
+
+    @every 1h from 00:15                     // at a quarter past every hour
+    @every 90m anchored 2026-01-01T00:00Z    // every 90 minutes counted from that instant
+
+With "from", the grid restarts each day at the given time of day, in the
+schedule's time zone, so the duration may be at most 24h.
+
+Sub-second schedules
+
+Durations with a fractional second, such as "@every 250ms" or "@every 1.5s",
+are not rounded: they produce a PreciseDelaySchedule (see EveryPrecisely).
+Parsers created with the Millisecond or MillisecondOptional option accept a
+leading millisecond field (0-999), which selects the milliseconds within each
+matching second:
+
+	p := cron.NewParser(cron.MillisecondOptional | cron.Second | cron.Minute |
+		cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	sched, err := p.Parse("0,250,500,750 * * * * * *") // four times a second
+
+The next activation of a PreciseDelaySchedule entry is computed from its
+previous activation rather than from the time the scheduler woke up, so short
+intervals stay on the same grid and do not drift.  Activations missed while
+the scheduler was busy are skipped.  Other schedules, including specs with a
+millisecond field, compute their next activation from the wake-up time as
+usual.
+
 Time zones
 
 By default, all interpretation and scheduling is done in the machine's local
@@ -166,8 +280,219 @@ For example:
 
 The prefix "TZ=(TIME ZONE)" is also supported for legacy compatibility.
 
-Be aware that jobs scheduled during daylight-savings leap-ahead transitions will
-not be run!
+By default, jobs scheduled during daylight-savings leap-ahead transitions will
+not be run, and jobs scheduled during fall-back transitions run twice, once for
+each occurrence of the local time.  A parser created with WithDSTPolicy can
+instead run skipped jobs at the first instant after the transition
+(DSTNextValid) and run repeated times only on their first occurrence (DSTOnce):
+
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).
+		WithDSTPolicy(cron.DSTNextValid | cron.DSTOnce)
+	c := cron.New(cron.WithParser(p))
+
+Parse errors
+
+Errors returned by a Parser are of type *ParseError, which records the name of
+the offending field, the byte offset and length of the offending token within
+the spec, and a machine-readable Reason:
+
+	_, err := cron.ParseStandard("0 9-25 * * *")
+	var perr *cron.ParseError
+	if errors.As(err, &perr) {
+		fmt.Println(perr.Field, perr.Offset, perr.Token, perr.Reason) // hour 2 9-25 out of range
+	}
+
+Linting schedules
+
+Some specs are valid but rarely what their author meant.  Parser.Lint parses a
+spec and reports such schedules as Warnings with a machine-readable Kind:
+schedules that never fire ("0 0 31 2 *"), a star minute or second field under
+a restricted hour or day ("* 3 * * *" runs sixty times), day-of-month and
+day-of-week combined with OR, items already covered by the rest of their field
+("1-10,5-7"), and activations that daylight saving time will skip or repeat in
+the coming year:
+
+	warnings, err := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).Lint("* 3 * * *")
+	for _, w := range warnings {
+		fmt.Println(w.Kind, w) // every minute minute field: runs every minute while ...
+	}
+
+SpecSchedule.Lint performs the same checks, except for redundant items, on a
+schedule that has already been parsed.
+
+Printing and persisting schedules
+
+SpecSchedule and ConstantDelaySchedule implement fmt.Stringer,
+encoding.TextMarshaler and encoding.TextUnmarshaler, so they can be shown in
+Entries() listings or stored as JSON strings.  The canonical form always
+includes the seconds field, compresses values back into ranges and steps, and
+keeps any CRON_TZ= prefix:
+
+	sched, _ := cron.ParseStandard("30 9-17 * * mon-fri")
+	fmt.Println(sched) // 0 30 9-17 * * 1-5
+
+A spec cannot express a DST policy other than the default, nor that a day must
+match both the day-of-month and day-of-week fields (the DayAnd option), so
+MarshalText returns an error for such schedules rather than losing the setting.
+
+Describing schedules
+
+Describe turns a schedule into English text, which is easier for operators to
+read than the spec itself:
+
+	sched, _ := cron.ParseStandard("0 9-17 * * mon-fri")
+	text, _ := cron.Describe(sched) // at minute 0 between 09:00 and 17:59, Monday through Friday
+
+Descriptions in other languages are produced by a Describer created with a
+different Catalog.  A Chinese catalog is provided:
+
+	text, _ = cron.NewDescriber(cron.Chinese).Describe(sched) // 周一至周五，09:00至17:59之间第0分钟
+
+Previous activations
+
+Schedules that can also compute their most recent activation implement the
+optional PrevSchedule interface, which SpecSchedule, ConstantDelaySchedule and
+AnchoredDelaySchedule do.  Prev returns the latest activation at or before the
+given time, following the same time zone and daylight-savings rules as Next.
+Schedules measured from whenever Next is called, such as "@every 1h", have no
+fixed activations; their Prev mirrors Next and returns one interval earlier:
+
+	if p, ok := entry.Schedule.(cron.PrevSchedule); ok {
+		fmt.Println("last expected run:", p.Prev(time.Now()))
+	}
+
+Upcoming activations
+
+NextN, Between and Iterator enumerate the activations of any Schedule.  They
+stop after MaxActivations, and report ErrNeverFires for schedules that never
+activate after the given time, such as "0 0 30 2 *":
+
+	week, err := cron.Between(sched, now, now.AddDate(0, 0, 7))
+
+Combining schedules
+
+Union, Intersect and Except combine existing schedules into a new one.  Except
+skips an activation of the base schedule only if the blackout schedule fires at
+exactly the same instant.  A blackout is not a window: "0 2 * * *" removes the
+02:00 activation and nothing else.  To black out a window, use ExceptFunc,
+which skips every activation for which a function returns true:
+
+	base, _ := cron.ParseStandard("0-59/5 * * * *")
+	c.Schedule(cron.ExceptFunc(base, func(t time.Time) bool {
+		return t.Hour() == 2
+	}), job) // every 5 minutes, except 02:00-03:00
+
+Intersect, Except and ExceptFunc give up, returning the zero time, when nothing
+satisfies the combination within five years.
+
+Random delays
+
+Jitter delays each activation of a schedule by a random duration below a bound,
+like systemd's RandomizedDelaySec, so that many processes sharing a schedule
+do not all hit a shared resource at the same moment.  A delayed activation
+always comes before the next activation of the underlying schedule.  Delays
+are derived from a seed, so tests can pass a fixed rand.Source:
+
+	sched, _ := cron.ParseStandard("@hourly")
+	c.Schedule(cron.Jitter(sched, 5*time.Minute, nil), job)
+
+Crontab files
+
+The crontab subpackage parses files in the crontab format, including comments,
+environment assignments such as CRON_TZ=, SHELL= and MAILTO=, and @reboot
+entries.  Errors report the offending line number.  Register adds the parsed
+entries to a Cron, using a factory to turn each command into a Job:
+
+	entries, err := crontab.Parse(f)
+	ids, err := crontab.Register(c, entries, func(e crontab.Entry) (cron.Job, error) {
+		return shellJob{e.Command, e.Env}, nil
+	})
+
+Business days
+
+A Calendar holds the weekend days (Saturday and Sunday by default) and a set of
+holidays, which may be loaded from a CSV or iCalendar file.  OnBusinessDays
+wraps any schedule so that activations falling on a non-business day are
+skipped, or moved to the next or previous business day at the same time of day:
+
+	holidays, err := cron.LoadCalendar("holidays.ics")
+	sched, _ := cron.ParseStandard("0 9 15 * *")
+	c.Schedule(cron.OnBusinessDays(sched, holidays, cron.PreviousBusinessDay), payroll)
+
+The "@businessday N [HH:MM]" descriptor runs on the Nth business day of each
+month, counting from the end of the month when N is negative.  Holidays are
+taken from the calendar given to Parser.WithCalendar:
+
+	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor).
+		WithCalendar(holidays)
+	sched, err := p.Parse("@businessday -1 18:00") // last business day of the month at 6pm
+
+Recurrence rules
+
+Parsers created with the RRule option parse specs beginning with "RRULE:" or
+"DTSTART" as iCalendar (RFC 5545) recurrence rules, with optional DTSTART and
+EXDATE properties separated by spaces.  FREQ, INTERVAL, COUNT, UNTIL, WKST and
+all BYxxx rule parts are supported:
+
+	c := cron.New(cron.WithParser(cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor | cron.RRule)))
+	c.AddFunc("DTSTART;TZID=Europe/Berlin:20260105T090000 RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10", standup)
+
+Without DTSTART, a rule starts when it is parsed.  ParseRRule parses a rule
+directly into an *RRuleSchedule.
+
+Repeating intervals
+
+ISO 8601 repeating intervals run at fixed intervals from a known start, rather
+than from whenever the job was added.  The optional count after "R" limits the
+total number of runs, and the duration may use calendar units such as P1M and
+P1W.  They are accepted by parsers created with the RepeatingInterval option:
+
+	c := cron.New(cron.WithParser(cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor | cron.RepeatingInterval)))
+	c.AddFunc("R5/2026-01-01T00:00:00Z/PT1H", job) // hourly, five times
+	c.AddFunc("R/2026-01-31T09:00:00+09:00/P1M", job) // monthly, on the last day in short months
+
+systemd calendar events
+
+SystemdParser accepts the calendar event syntax of systemd timers (OnCalendar=),
+including weekday ranges, "~" for days counted from the end of the month, a
+trailing time zone and shorthands such as "daily" and "weekly".  Unlike cron,
+a weekday and a day of month must both match:
+
+	c := cron.New(cron.WithParser(cron.SystemdParser{}))
+	c.AddFunc("Mon..Fri *-*-* 09:00:00", job)
+	c.AddFunc("Mon *-05~07/1 12:00 Europe/Berlin", job) // last Monday of May
+
+Deployment dialects
+
+EventBridgeParser and KubernetesParser accept exactly what AWS EventBridge and
+Kubernetes CronJobs accept, so schedules can be validated before they are
+deployed.  EventBridgeParser takes "cron(...)" expressions with six fields, a
+mandatory "?" in the day-of-month or day-of-week field, weekdays numbered 1-7
+from Sunday and years up to 2199, as well as "rate(5 minutes)".
+KubernetesParser takes the standard five fields and the usual descriptors, but
+not "@every" or a TZ= prefix.  Neither accepts this package's extensions, such
+as ranges that wrap around or "?" outside the day fields.  Anything a dialect
+does not accept is reported as a *ParseError with Reason ReasonDialect:
+
+	_, err := cron.KubernetesParser{}.Parse("@every 1h") // ReasonDialect
+	_, err = cron.EventBridgeParser{}.Parse("cron(0 12 ? * MON-FRI *)")
+
+Context-aware jobs
+
+Stop does not interrupt running jobs, and a plain Job has no way to learn that
+it was called.  Jobs added with AddFuncCtx or AddJobCtx receive a
+context.Context instead, which is cancelled when the Cron is stopped or the
+entry is removed.  The context also carries the entry ID, the scheduled time
+and the actual start time of the run, and a returned error is logged:
+
+	c.AddFuncCtx("@every 1m", func(ctx context.Context) error {
+		info, _ := cron.RunInfoFromContext(ctx)
+		return sync(ctx, info.Scheduled) // returns early once ctx is done
+	})
+	..
+	<-c.Stop().Done() // cancels sync's context and waits for it to return
 
 Job Wrappers
 
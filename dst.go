@@ -0,0 +1,178 @@
+package cron
+
+import (
+	"sort"
+	"time"
+)
+
+// DSTPolicy指定SpecSchedule如何处理夏令时转换中不存在或重复的本地时间。
+// 不存在的时间（时钟向前拨）和重复的时间（时钟向后拨）各有两种处理方式，
+// 可以用"|"各选一种组合起来，未指定的一方使用默认方式。零值等同于DSTSkip|DSTTwice。
+type DSTPolicy int
+
+const (
+	DSTSkip      DSTPolicy = 1 << iota // 不存在的本地时间不激活（默认）
+	DSTNextValid                       // 不存在的本地时间改为在转换后的第一个时刻激活一次
+	DSTOnce                            // 重复的本地时间只在第一次出现时激活
+	DSTTwice                           // 重复的本地时间在两次出现时都激活（默认）
+)
+
+// next实现了整秒的Next，并按照s.DST处理夏令时转换。
+func (s *SpecSchedule) next(t time.Time) time.Time {
+	next := s.nextMatch(t)
+	if s.DST&(DSTNextValid|DSTOnce) == 0 {
+		return next
+	}
+
+	loc := s.zone(t)
+	if s.DST&DSTOnce > 0 {
+		// 重复时间的第二次出现都在转换后的一段时间内，直接从这段时间的末尾继续查找。
+		for !next.IsZero() {
+			first, ok := firstOccurrence(next, loc)
+			if !ok {
+				break
+			}
+			end := transition(first, next, loc).Add(next.Sub(first))
+			next = s.nextMatch(end.Add(-time.Nanosecond))
+		}
+	}
+	if s.DST&DSTNextValid > 0 {
+		if gap := s.nextGap(t, next, loc); !gap.IsZero() {
+			return gap.In(t.Location())
+		}
+	}
+	return next
+}
+
+// prev实现了整秒的Prev，并按照s.DST处理夏令时转换。
+func (s *SpecSchedule) prev(t time.Time) time.Time {
+	prev := s.prevMatch(t)
+	if s.DST&(DSTNextValid|DSTOnce) == 0 {
+		return prev
+	}
+
+	loc := s.zone(t)
+	if s.DST&DSTOnce > 0 {
+		for !prev.IsZero() {
+			first, ok := firstOccurrence(prev, loc)
+			if !ok {
+				break
+			}
+			prev = s.prevMatch(transition(first, prev, loc).Add(-time.Nanosecond))
+		}
+	}
+	if s.DST&DSTNextValid > 0 {
+		if gap := s.prevGap(t, prev, loc); !gap.IsZero() {
+			return gap.In(t.Location())
+		}
+	}
+	return prev
+}
+
+// nextGap返回t之后、早于limit的第一个夏令时转换时刻，时间表在该转换跳过的本地时间上匹配。
+// limit为零值时不限制。如果没有这样的转换，则返回时间的零值。
+func (s *SpecSchedule) nextGap(t, limit time.Time, loc *time.Location) time.Time {
+	// 在UTC中按本地时间（墙上时间）查找匹配，找出其中不存在的本地时间。
+	wallClock := *s
+	wallClock.Location = time.UTC
+	end := wall(limit, loc)
+	for w := wallClock.nextMatch(wall(t, loc)); !w.IsZero(); w = wallClock.nextMatch(w) {
+		if !limit.IsZero() && !w.Before(end) {
+			break
+		}
+		if len(instants(w, loc)) == 0 {
+			return gapEnd(w, loc)
+		}
+	}
+	return time.Time{}
+}
+
+// prevGap返回不晚于t、晚于limit的最后一个夏令时转换时刻，时间表在该转换跳过的本地时间上匹配。
+func (s *SpecSchedule) prevGap(t, limit time.Time, loc *time.Location) time.Time {
+	wallClock := *s
+	wallClock.Location = time.UTC
+	end := wall(limit, loc)
+	for w := wallClock.prevMatch(wall(t, loc)); !w.IsZero(); w = wallClock.prevMatch(w.Add(-time.Second)) {
+		if !limit.IsZero() && !w.After(end) {
+			break
+		}
+		if len(instants(w, loc)) == 0 {
+			if gap := gapEnd(w, loc); !gap.After(t) {
+				return gap
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// zone返回计算时间表时使用的时区：没有指定时区（time.Local）的时间表使用t的时区。
+func (s *SpecSchedule) zone(t time.Time) *time.Location {
+	if s.Location == time.Local {
+		return t.Location()
+	}
+	return s.Location
+}
+
+// wall返回t在loc中的本地时间，表示为UTC中的同一时刻，以便不受夏令时影响地比较和查找。
+func wall(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// instants返回loc中本地时间为w（由wall表示）的所有时刻，按时间顺序排列。
+// 不存在的本地时间返回空，重复的本地时间返回两个时刻。
+func instants(w time.Time, loc *time.Location) []time.Time {
+	// 转换前后的偏移可以从前后一天的时刻中得到。
+	var result []time.Time
+	for _, probe := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := w.Add(probe).In(loc).Zone()
+		at := w.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, actual := at.Zone(); actual != offset || containsTime(result, at) {
+			continue
+		}
+		result = append(result, at)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+// containsTime返回times中是否有与t相同的时刻。
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, other := range times {
+		if other.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstOccurrence返回t是否为重复本地时间的第二次出现，以及该本地时间第一次出现的时刻。
+func firstOccurrence(t time.Time, loc *time.Location) (time.Time, bool) {
+	at := instants(wall(t, loc), loc)
+	if len(at) == 2 && at[1].Equal(t) {
+		return at[0], true
+	}
+	return time.Time{}, false
+}
+
+// gapEnd返回跳过本地时间w的夏令时转换时刻，即转换后的第一个时刻。
+func gapEnd(w time.Time, loc *time.Location) time.Time {
+	_, before := w.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := w.Add(24 * time.Hour).In(loc).Zone()
+	return transition(w.Add(-time.Duration(after)*time.Second), w.Add(-time.Duration(before)*time.Second), loc)
+}
+
+// transition返回(a, b]中偏移与a不同的第一个整秒，a与b之间必须恰好有一次转换。
+func transition(a, b time.Time, loc *time.Location) time.Time {
+	_, offset := a.In(loc).Zone()
+	lo, hi := a.Unix(), b.Unix()
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if _, o := time.Unix(mid, 0).In(loc).Zone(); o == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return time.Unix(hi, 0).In(loc)
+}
@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDSTPolicy(t *testing.T) {
+	tests := []struct {
+		spec     string
+		policy   DSTPolicy
+		time     string
+		expected []string
+	}{
+		// New York: 2am EST -> 3am EDT, then 2am EDT -> 1am EST.
+		{"CRON_TZ=America/New_York 30 2 * * *", 0, "2012-03-10T12:00:00-0500",
+			[]string{"2012-03-12T02:30:00-0400", "2012-03-13T02:30:00-0400"}},
+		{"CRON_TZ=America/New_York 30 2 * * *", DSTSkip, "2012-03-10T12:00:00-0500",
+			[]string{"2012-03-12T02:30:00-0400", "2012-03-13T02:30:00-0400"}},
+		{"CRON_TZ=America/New_York 30 2 * * *", DSTNextValid, "2012-03-10T12:00:00-0500",
+			[]string{"2012-03-11T03:00:00-0400", "2012-03-12T02:30:00-0400"}},
+		{"CRON_TZ=America/New_York * 2 * * *", DSTNextValid, "2012-03-11T01:58:00-0500",
+			[]string{"2012-03-11T03:00:00-0400", "2012-03-12T02:00:00-0400"}},
+		{"CRON_TZ=America/New_York 0 * * * *", DSTNextValid, "2012-03-11T00:30:00-0500",
+			[]string{"2012-03-11T01:00:00-0500", "2012-03-11T03:00:00-0400", "2012-03-11T04:00:00-0400"}},
+		{"CRON_TZ=America/New_York */30 1 * * *", 0, "2012-11-04T00:00:00-0400",
+			[]string{"2012-11-04T01:00:00-0400", "2012-11-04T01:30:00-0400", "2012-11-04T01:00:00-0500", "2012-11-04T01:30:00-0500", "2012-11-05T01:00:00-0500"}},
+		{"CRON_TZ=America/New_York */30 1 * * *", DSTTwice, "2012-11-04T00:00:00-0400",
+			[]string{"2012-11-04T01:00:00-0400", "2012-11-04T01:30:00-0400", "2012-11-04T01:00:00-0500", "2012-11-04T01:30:00-0500", "2012-11-05T01:00:00-0500"}},
+		{"CRON_TZ=America/New_York */30 1 * * *", DSTOnce, "2012-11-04T00:00:00-0400",
+			[]string{"2012-11-04T01:00:00-0400", "2012-11-04T01:30:00-0400", "2012-11-05T01:00:00-0500"}},
+		{"CRON_TZ=America/New_York */30 1 * * *", DSTOnce, "2012-11-04T01:15:00-0500",
+			[]string{"2012-11-05T01:00:00-0500"}},
+		{"CRON_TZ=America/New_York 0 * * * *", DSTOnce | DSTNextValid, "2012-11-04T00:30:00-0400",
+			[]string{"2012-11-04T01:00:00-0400", "2012-11-04T02:00:00-0500", "2012-11-04T03:00:00-0500"}},
+
+		// London: 1am GMT -> 2am BST, then 2am BST -> 1am GMT.
+		{"CRON_TZ=Europe/London 30 1 * * *", DSTSkip, "2012-03-24T12:00:00+0000",
+			[]string{"2012-03-26T01:30:00+0100"}},
+		{"CRON_TZ=Europe/London 30 1 * * *", DSTNextValid, "2012-03-24T12:00:00+0000",
+			[]string{"2012-03-25T02:00:00+0100", "2012-03-26T01:30:00+0100"}},
+		{"CRON_TZ=Europe/London 30 1 * * *", DSTTwice, "2012-10-27T12:00:00+0100",
+			[]string{"2012-10-28T01:30:00+0100", "2012-10-28T01:30:00+0000", "2012-10-29T01:30:00+0000"}},
+		{"CRON_TZ=Europe/London 30 1 * * *", DSTOnce, "2012-10-27T12:00:00+0100",
+			[]string{"2012-10-28T01:30:00+0100", "2012-10-29T01:30:00+0000"}},
+
+		// Lord Howe Island moves its clocks by half an hour: 2am -> 2:30am, then 2am -> 1:30am.
+		{"CRON_TZ=Australia/Lord_Howe 15 2 * * *", DSTSkip, "2012-10-06T12:00:00+1030",
+			[]string{"2012-10-08T02:15:00+1100"}},
+		{"CRON_TZ=Australia/Lord_Howe 15 2 * * *", DSTNextValid, "2012-10-06T12:00:00+1030",
+			[]string{"2012-10-07T02:30:00+1100", "2012-10-08T02:15:00+1100"}},
+		{"CRON_TZ=Australia/Lord_Howe 45 1 * * *", DSTTwice, "2012-03-31T12:00:00+1100",
+			[]string{"2012-04-01T01:45:00+1100", "2012-04-01T01:45:00+1030", "2012-04-02T01:45:00+1030"}},
+		{"CRON_TZ=Australia/Lord_Howe 45 1 * * *", DSTOnce, "2012-03-31T12:00:00+1100",
+			[]string{"2012-04-01T01:45:00+1100", "2012-04-02T01:45:00+1030"}},
+
+		// São Paulo changed its clocks at midnight: midnight -> 1am, then midnight -> 11pm.
+		{"CRON_TZ=America/Sao_Paulo 0 0 * * *", DSTSkip, "2018-11-03T12:00:00-0300",
+			[]string{"2018-11-05T00:00:00-0200"}},
+		{"CRON_TZ=America/Sao_Paulo 0 0 * * *", DSTNextValid, "2018-11-03T12:00:00-0300",
+			[]string{"2018-11-04T01:00:00-0200", "2018-11-05T00:00:00-0200"}},
+		{"CRON_TZ=America/Sao_Paulo 30 23 * * *", DSTTwice, "2018-02-17T12:00:00-0200",
+			[]string{"2018-02-17T23:30:00-0200", "2018-02-17T23:30:00-0300", "2018-02-18T23:30:00-0300"}},
+		{"CRON_TZ=America/Sao_Paulo 30 23 * * *", DSTOnce, "2018-02-17T12:00:00-0200",
+			[]string{"2018-02-17T23:30:00-0200", "2018-02-18T23:30:00-0300"}},
+	}
+	for _, test := range tests {
+		schedule, err := standardParser.WithDSTPolicy(test.policy).Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		spec := schedule.(*SpecSchedule)
+		if spec.DST != test.policy {
+			t.Errorf("%s => expected policy %v, got %v", test.spec, test.policy, spec.DST)
+		}
+
+		next := getTime(test.time)
+		for i, expected := range getTimes(test.expected) {
+			if next = spec.Next(next); !next.Equal(expected) {
+				t.Errorf("%s (%v) from %s, activation %d: (expected) %v != %v (actual)", test.spec, test.policy, test.time, i, expected, next)
+				break
+			}
+
+			// Prev follows the same policy.
+			if prev := spec.Prev(next); !prev.Equal(next) {
+				t.Errorf("%s (%v) Prev(%v) = %v", test.spec, test.policy, next, prev)
+			}
+			if i > 0 {
+				if prev := spec.Prev(next.Add(-time.Nanosecond)); !prev.Equal(getTime(test.expected[i-1])) {
+					t.Errorf("%s (%v) Prev(%v) = %v, expected %s", test.spec, test.policy, next.Add(-time.Nanosecond), prev, test.expected[i-1])
+				}
+			}
+		}
+	}
+}
+
+func TestDSTPolicyMilliseconds(t *testing.T) {
+	p := NewParser(MillisecondOptional | Second | Minute | Hour | Dom | Month | Dow | Year).WithDSTPolicy(DSTNextValid)
+	schedule, err := p.Parse("CRON_TZ=America/New_York 500 0 30 2 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := schedule.Next(getTime("2012-03-11T00:00:00-0500"))
+	if expected := getTime("2012-03-11T03:00:00-0400").Add(500 * time.Millisecond); !actual.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestWithDSTPolicyPanics(t *testing.T) {
+	for _, policy := range []DSTPolicy{DSTSkip | DSTNextValid, DSTOnce | DSTTwice} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v: expected a panic", policy)
+				}
+			}()
+			standardParser.WithDSTPolicy(policy)
+		}()
+	}
+}
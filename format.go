@@ -0,0 +1,194 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// canonicalParser接受String生成的所有spec：秒字段、可选的年份字段、修饰符和描述符，
+// 以及带毫秒字段和年份字段的八个字段的spec。
+var canonicalParser = canonicalSpecParser{
+	seconds:      NewParser(Second | Minute | Hour | Dom | Month | Dow | YearOptional | Descriptor | QuartzModifiers),
+	milliseconds: NewParser(Millisecond | Second | Minute | Hour | Dom | Month | Dow | Year | Descriptor | QuartzModifiers),
+}
+
+// canonicalSpecParser按字段的数量选择解析器：两个可选字段无法由同一个Parser区分。
+// 八个字段的spec只有在确实带有毫秒时才是规范的，否则按六到七个字段报告错误。
+type canonicalSpecParser struct {
+	seconds, milliseconds Parser
+}
+
+func (p canonicalSpecParser) Parse(spec string) (Schedule, error) {
+	fields, _ := splitFields(spec)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=")) {
+		fields = fields[1:]
+	}
+	if len(fields) == 8 {
+		schedule, err := p.milliseconds.Parse(spec)
+		if s, ok := schedule.(*SpecSchedule); err == nil && ok && s.Millisecond != nil {
+			return schedule, nil
+		}
+	}
+	return p.seconds.Parse(spec)
+}
+
+// String返回该时间表的规范spec：总是包含秒字段，只有限定了年份时才包含年份字段，
+// 时区不是time.Local时带有"CRON_TZ="前缀。位集会被压缩回范围和步长，
+// 月份和星期使用数字表示。带毫秒的时间表在最前面另有毫秒字段，并且总是包含年份字段。
+// spec无法表示夏令时策略和DomAndDow，因此结果不包含它们：解析结果得到的是默认的策略，
+// 并且日期和星期都受限时按Vixie的"或"语义匹配。因此丢失而改变激活时间的时间表会使MarshalText返回错误。
+// 除此之外，使用启用了Second、YearOptional和QuartzModifiers的解析器解析结果，会得到相同的时间表。
+func (s *SpecSchedule) String() string {
+	fields := []string{
+		formatField(s.Second, seconds, nil),
+		formatField(s.Minute, minutes, nil),
+		formatField(s.Hour, hours, nil),
+		formatField(s.Dom, dom, domModifierExprs(s)),
+		formatField(s.Month, months, nil),
+		formatField(s.Dow, dow, dowModifierExprs(s)),
+	}
+	if s.Millisecond != nil {
+		fields = append([]string{formatValues(s.Millisecond, int(milliseconds.min), int(milliseconds.max))}, fields...)
+		if s.Year == nil {
+			fields = append(fields, "*")
+		}
+	}
+	if s.Year != nil {
+		fields = append(fields, formatValues(s.Year, int(years.min), int(years.max)))
+	}
+
+	spec := strings.Join(fields, " ")
+	if s.Location != nil && s.Location != time.Local {
+		spec = "CRON_TZ=" + s.Location.String() + " " + spec
+	}
+	return spec
+}
+
+// MarshalText实现了encoding.TextMarshaler，返回String的结果。
+// 如果String无法完整表示该时间表（使用了非默认的夏令时策略，或者日期和星期都受限时
+// 要求同时满足），则返回错误，以免UnmarshalText悄悄得到一个不同的时间表。
+func (s *SpecSchedule) MarshalText() ([]byte, error) {
+	if s.DST&(DSTNextValid|DSTOnce) > 0 {
+		return nil, fmt.Errorf("cannot marshal a schedule with a non-default DST policy: %s", s)
+	}
+	if s.DomAndDow && s.Dom&starBit == 0 && s.Dow&starBit == 0 {
+		return nil, fmt.Errorf("cannot marshal a schedule matching both day of month and day of week: %s", s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText实现了encoding.TextUnmarshaler，解析由String（或MarshalText）生成的spec。
+func (s *SpecSchedule) UnmarshalText(text []byte) error {
+	schedule, err := canonicalParser.Parse(string(text))
+	if err != nil {
+		return err
+	}
+	spec, ok := schedule.(*SpecSchedule)
+	if !ok {
+		return fmt.Errorf("not a spec schedule: %s", text)
+	}
+	*s = *spec
+	return nil
+}
+
+// formatField将字段的位集转换为规范的表达式，并追加给定的修饰符表达式。
+func formatField(bits uint64, r bounds, modifiers []string) string {
+	var exprs []string
+	if bits&starBit > 0 {
+		exprs = append(exprs, "*")
+	} else if values := fieldValues(bits, r); len(values) > 0 {
+		exprs = append(exprs, formatValues(values, int(r.min), int(r.max)))
+	}
+	return strings.Join(append(exprs, modifiers...), ",")
+}
+
+// fieldValues返回位集在[min，max]范围内设置的所有值（升序）。
+func fieldValues(bits uint64, r bounds) []int {
+	var values []int
+	for i := r.min; i <= r.max; i++ {
+		if 1<<i&bits > 0 {
+			values = append(values, int(i))
+		}
+	}
+	return values
+}
+
+// formatValues将升序的值压缩为最短的常见形式：
+// 如果它们构成步长大于1的等差数列，则返回"*/step"、"start/step"或"start-end/step"；
+// 否则返回用逗号分隔的单个值和连续区间。
+func formatValues(values []int, min, max int) string {
+	if len(values) > 2 {
+		step := values[1] - values[0]
+		progression := step > 1
+		for i := 2; progression && i < len(values); i++ {
+			progression = values[i]-values[i-1] == step
+		}
+		if progression {
+			first, last := values[0], values[len(values)-1]
+			switch {
+			case last+step <= max:
+				return fmt.Sprintf("%d-%d/%d", first, last, step)
+			case first == min:
+				return fmt.Sprintf("*/%d", step)
+			default:
+				return fmt.Sprintf("%d/%d", first, step)
+			}
+		}
+	}
+
+	var exprs []string
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+		if i == j {
+			exprs = append(exprs, fmt.Sprint(values[i]))
+		} else {
+			exprs = append(exprs, fmt.Sprintf("%d-%d", values[i], values[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(exprs, ",")
+}
+
+// domModifierExprs返回s中Dom字段修饰符的表达式。
+func domModifierExprs(s *SpecSchedule) []string {
+	var exprs []string
+	for i := uint(0); i <= dom.max-dom.min; i++ {
+		if 1<<i&s.DomLast == 0 {
+			continue
+		}
+		if i == 0 {
+			exprs = append(exprs, "L")
+		} else {
+			exprs = append(exprs, fmt.Sprintf("L-%d", i))
+		}
+	}
+	if s.DomWeekday&1 > 0 {
+		exprs = append(exprs, "LW")
+	}
+	for d := dom.min; d <= dom.max; d++ {
+		if 1<<d&s.DomWeekday > 0 {
+			exprs = append(exprs, fmt.Sprintf("%dW", d))
+		}
+	}
+	return exprs
+}
+
+// dowModifierExprs返回s中Dow字段修饰符的表达式。
+func dowModifierExprs(s *SpecSchedule) []string {
+	var exprs []string
+	for d := dow.min; d <= dow.max; d++ {
+		if 1<<d&s.DowLast > 0 {
+			exprs = append(exprs, fmt.Sprintf("%dL", d))
+		}
+	}
+	for i := uint(0); i < 5*7; i++ {
+		if 1<<i&s.DowNth > 0 {
+			exprs = append(exprs, fmt.Sprintf("%d#%d", i%7, i/7+1))
+		}
+	}
+	return exprs
+}
@@ -0,0 +1,201 @@
+package cron
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSpecScheduleString(t *testing.T) {
+	yearParser := NewParser(Minute | Hour | Dom | Month | Dow | YearOptional | QuartzModifiers)
+	entries := []struct {
+		parser   Parser
+		expr     string
+		expected string
+	}{
+		{standardParser, "* * * * *", "0 * * * * *"},
+		{standardParser, "*/15 9-17 * * mon-fri", "0 */15 9-17 * * 1-5"},
+		{standardParser, "0/15 * * * *", "0 */15 * * * *"},
+		{standardParser, "5/15 * * * *", "0 5/15 * * * *"},
+		{standardParser, "5-40/15 * * * *", "0 5-35/15 * * * *"},
+		{standardParser, "0-59 0 1,2,3,5,7,8 * *", "0 0-59 0 1-3,5,7-8 * *"},
+		{standardParser, "0 0 ? jan,jul *", "0 0 0 * 1,7 *"},
+		{standardParser, "0 0 * * 0,3,6", "0 0 0 * * */3"},
+		{standardParser, "0 22-2 * * fri-mon", "0 0 0-2,22-23 * * 0-1,5-6"},
+		{standardParser, "CRON_TZ=UTC 0 6 * * *", "CRON_TZ=UTC 0 0 6 * * *"},
+		{standardParser, "CRON_TZ=Asia/Tokyo 30 4 * * *", "CRON_TZ=Asia/Tokyo 0 30 4 * * *"},
+		{standardParser, "@daily", "0 0 0 * * *"},
+		{standardParser, "@weekly", "0 0 0 * * 0"},
+		{secondParser, "15/35 20-35/15 1/2 */2 *", "15,50 20,35 1/2 */2 * *"},
+		{yearParser, "0 0 L,15 * ?", "0 0 0 15,L * *"},
+		{yearParser, "0 0 L-3,LW,15W * *", "0 0 0 L-3,LW,15W * *"},
+		{yearParser, "0 0 ? * fri#3,6L,1", "0 0 0 * * 1,6L,5#3"},
+		{yearParser, "0 0 1 1 * 2027-2029", "0 0 0 1 1 * 2027-2029"},
+		{yearParser, "0 0 1 1 * 2030-2040/5", "0 0 0 1 1 * 2030-2040/5"},
+		{yearParser, "0 0 1 1 * */50", "0 0 0 1 1 * */50"},
+	}
+
+	// The parsers use the default DST policy and day matching, which String can represent.
+	for _, c := range entries {
+		sched, err := c.parser.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		actual := sched.(*SpecSchedule).String()
+		if actual != c.expected {
+			t.Errorf("%s => expected %q, got %q", c.expr, c.expected, actual)
+		}
+
+		reparsed, err := canonicalParser.Parse(actual)
+		if err != nil {
+			t.Errorf("%s => unexpected error reparsing %q: %v", c.expr, actual, err)
+			continue
+		}
+		if !equalSpecSchedules(sched.(*SpecSchedule), reparsed.(*SpecSchedule)) {
+			t.Errorf("%s => reparsing %q gave %+v, expected %+v", c.expr, actual, reparsed, sched)
+		}
+	}
+}
+
+func TestSpecScheduleMarshalDST(t *testing.T) {
+	tests := []struct {
+		policy DSTPolicy
+		ok     bool
+	}{
+		{0, true},
+		{DSTSkip | DSTTwice, true},
+		{DSTNextValid, false},
+		{DSTOnce, false},
+		{DSTNextValid | DSTOnce, false},
+	}
+	for _, test := range tests {
+		sched, _ := standardParser.WithDSTPolicy(test.policy).Parse("CRON_TZ=America/New_York 30 2 * * *")
+		spec := sched.(*SpecSchedule)
+		if expected := "CRON_TZ=America/New_York 0 30 2 * * *"; spec.String() != expected {
+			t.Errorf("%v => expected %q, got %q", test.policy, expected, spec.String())
+		}
+
+		// The policy cannot be written in a spec, so marshaling refuses to lose it.
+		text, err := spec.MarshalText()
+		if test.ok && (err != nil || string(text) != spec.String()) {
+			t.Errorf("%v => expected %q, got %q (%v)", test.policy, spec.String(), text, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("%v => expected an error, got %q", test.policy, text)
+		}
+	}
+
+	sched, _ := standardParser.WithDSTPolicy(DSTNextValid).Parse("30 2 * * *")
+	if _, err := json.Marshal(struct{ Spec *SpecSchedule }{sched.(*SpecSchedule)}); err == nil {
+		t.Error("expected an error marshaling JSON")
+	}
+}
+
+func TestSpecScheduleMarshalDomAndDow(t *testing.T) {
+	and := NewParser(Minute | Hour | Dom | Month | Dow | DayAnd)
+	tests := []struct {
+		parser   Parser
+		spec     string
+		expected string
+		ok       bool
+	}{
+		{and, "0 0 13 * fri", "0 0 0 13 * 5", false},
+		{standardParser, "0 0 13 * fri", "0 0 0 13 * 5", true},
+
+		// With either field unrestricted, both semantics select the same days.
+		{and, "0 0 13 * *", "0 0 0 13 * *", true},
+		{and, "0 0 * * fri", "0 0 0 * * 5", true},
+	}
+	for _, test := range tests {
+		sched, _ := test.parser.Parse(test.spec)
+		spec := sched.(*SpecSchedule)
+		if spec.String() != test.expected {
+			t.Errorf("%s => expected %q, got %q", test.spec, test.expected, spec.String())
+		}
+
+		text, err := spec.MarshalText()
+		if !test.ok {
+			if err == nil {
+				t.Errorf("%s => expected an error, got %q", test.spec, text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		var decoded SpecSchedule
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		from := getTime("Mon Jul 9 00:00 2012")
+		expected, _ := NextN(spec, from, 10)
+		actual, _ := NextN(&decoded, from, 10)
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s => decoded schedule activates at %v, expected %v", test.spec, actual, expected)
+		}
+	}
+}
+
+func TestSpecScheduleMarshalJSON(t *testing.T) {
+	var value struct {
+		Spec  *SpecSchedule
+		Every ConstantDelaySchedule
+	}
+	sched, _ := ParseStandard("CRON_TZ=America/New_York */15 9-17 * * mon-fri")
+	value.Spec = sched.(*SpecSchedule)
+	value.Every = Every(90 * time.Minute)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"Spec":"CRON_TZ=America/New_York 0 */15 9-17 * * 1-5","Every":"@every 1h30m0s"}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	var decoded struct {
+		Spec  *SpecSchedule
+		Every ConstantDelaySchedule
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !equalSpecSchedules(decoded.Spec, value.Spec) {
+		t.Errorf("expected %+v, got %+v", value.Spec, decoded.Spec)
+	}
+	if decoded.Every != value.Every {
+		t.Errorf("expected %v, got %v", value.Every, decoded.Every)
+	}
+}
+
+func TestUnmarshalTextErrors(t *testing.T) {
+	var spec SpecSchedule
+	if err := spec.UnmarshalText([]byte("@every 5m")); err == nil || !strings.Contains(err.Error(), "not a spec schedule") {
+		t.Errorf("expected an error, got %v", err)
+	}
+	if err := spec.UnmarshalText([]byte("0 0 0 * * * * *")); err == nil {
+		t.Error("expected an error, got none")
+	}
+
+	var every ConstantDelaySchedule
+	if err := every.UnmarshalText([]byte("@daily")); err == nil || !strings.Contains(err.Error(), "not a constant delay schedule") {
+		t.Errorf("expected an error, got %v", err)
+	}
+}
+
+// equalSpecSchedules compares two schedules, treating locations with the same
+// name as equal.
+func equalSpecSchedules(a, b *SpecSchedule) bool {
+	x, y := *a, *b
+	if x.Location.String() != y.Location.String() {
+		return false
+	}
+	x.Location, y.Location = nil, nil
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,286 @@
+package cron
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Period是ISO 8601中的时长，例如"P1M"、"P1W"或"PT1H30M"。
+// 年、月和天按日历计算（保持当地时刻不变），其余部分按绝对时长计算。
+type Period struct {
+	Years, Months, Days int
+	Duration            time.Duration
+}
+
+// ParsePeriod解析ISO 8601格式的时长：
+//   "P" [n "Y"] [n "M"] [n "W"] [n "D"] [ "T" [n "H"] [n "M"] [n "S"] ]
+// 周按7天计算，只有秒可以带小数。
+func ParsePeriod(s string) (Period, error) {
+	var (
+		p        Period
+		rest     = s
+		inTime   bool
+		units    = "YMWD"
+		hasValue bool
+	)
+	if !strings.HasPrefix(rest, "P") {
+		return p, parseErrorf(ReasonSyntax, "duration must start with P: %s", s)
+	}
+	rest = rest[1:]
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return p, parseErrorf(ReasonSyntax, "malformed duration: %s", s)
+			}
+			inTime, units, rest = true, "HMS", rest[1:]
+			continue
+		}
+
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return p, parseErrorf(ReasonSyntax, "malformed duration: %s", s)
+		}
+		number, unit := strings.Replace(rest[:i], ",", ".", 1), rest[i]
+		rest = rest[i+1:]
+
+		// 单位必须按顺序出现，且每个最多一次。
+		u := strings.IndexByte(units, unit)
+		if u < 0 {
+			return p, parseErrorf(ReasonSyntax, "unexpected unit %c in duration: %s", unit, s)
+		}
+		units = units[u+1:]
+		hasValue = true
+
+		if unit == 'S' && inTime {
+			seconds, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return p, parseErrorf(ReasonSyntax, "malformed duration: %s", s)
+			}
+			p.Duration += time.Duration(seconds * float64(time.Second))
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return p, parseErrorf(ReasonSyntax, "only seconds may be fractional: %s", s)
+		}
+		switch {
+		case !inTime && unit == 'Y':
+			p.Years = n
+		case !inTime && unit == 'M':
+			p.Months = n
+		case !inTime && unit == 'W':
+			p.Days += 7 * n
+		case !inTime && unit == 'D':
+			p.Days += n
+		case unit == 'H':
+			p.Duration += time.Duration(n) * time.Hour
+		case unit == 'M':
+			p.Duration += time.Duration(n) * time.Minute
+		}
+	}
+	if !hasValue {
+		return p, parseErrorf(ReasonSyntax, "empty duration: %s", s)
+	}
+	return p, nil
+}
+
+// IsZero报告该时长是否为零。
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// AddTo返回t之后n个时长的时间。年和月超出月末时取月末，例如1月31日加上P1M为2月28日（或29日）。
+func (p Period) AddTo(t time.Time, n int) time.Time {
+	if months := (p.Years*12 + p.Months) * n; months != 0 {
+		year, month, day := t.Date()
+		hour, min, sec := t.Clock()
+		first := time.Date(year, month+time.Month(months), 1, hour, min, sec, t.Nanosecond(), t.Location())
+		if last := daysIn(first.Year(), first.Month()); day > last {
+			day = last
+		}
+		t = first.AddDate(0, 0, day-1)
+	}
+	if p.Days != 0 {
+		t = t.AddDate(0, 0, p.Days*n)
+	}
+	return t.Add(p.Duration * time.Duration(n))
+}
+
+// longest返回该时长可能的最大长度（闰年、大月、夏令时结束的一天），
+// 用于估计跳过的次数而不会跳过头。超出time.Duration的范围（约292年）时返回最大的time.Duration。
+func (p Period) longest() time.Duration {
+	longest := p.Duration
+	for _, part := range []struct {
+		n    int
+		unit time.Duration
+	}{
+		{p.Years, 366 * 24 * time.Hour},
+		{p.Months, 31 * 24 * time.Hour},
+		{p.Days, 25 * time.Hour},
+	} {
+		if part.n > 0 && (time.Duration(part.n) > math.MaxInt64/part.unit || longest > math.MaxInt64-time.Duration(part.n)*part.unit) {
+			return math.MaxInt64
+		}
+		longest += time.Duration(part.n) * part.unit
+	}
+	return longest
+}
+
+// String返回ISO 8601格式的时长，例如"P1MT12H"。
+func (p Period) String() string {
+	var b strings.Builder
+	b.WriteString("P")
+	if p.Years != 0 {
+		fmt.Fprintf(&b, "%dY", p.Years)
+	}
+	if p.Months != 0 {
+		fmt.Fprintf(&b, "%dM", p.Months)
+	}
+	if p.Days != 0 {
+		if p.Days%7 == 0 && p.Years == 0 && p.Months == 0 && p.Duration == 0 {
+			fmt.Fprintf(&b, "%dW", p.Days/7)
+		} else {
+			fmt.Fprintf(&b, "%dD", p.Days)
+		}
+	}
+	if d := p.Duration; d != 0 || p.IsZero() {
+		b.WriteString("T")
+		if h := d / time.Hour; h != 0 {
+			fmt.Fprintf(&b, "%dH", h)
+			d -= h * time.Hour
+		}
+		if m := d / time.Minute; m != 0 {
+			fmt.Fprintf(&b, "%dM", m)
+			d -= m * time.Minute
+		}
+		if d != 0 || p.IsZero() {
+			b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "S")
+		}
+	}
+	return b.String()
+}
+
+// IntervalSchedule是ISO 8601的重复时间间隔，例如"R5/2026-01-01T00:00:00Z/PT1H"：
+// 从Start开始，每隔Period激活一次。激活时间总是从Start算起，因此不会随Cron.Start的时间漂移。
+type IntervalSchedule struct {
+	Start  time.Time
+	Period Period
+
+	// Repeat是激活的总次数（包括Start），负数表示不限。
+	Repeat int
+}
+
+// Next返回晚于t的下一个激活时间。如果已经激活了Repeat次，则返回时间的零值。
+func (s IntervalSchedule) Next(t time.Time) time.Time {
+	if s.Period.IsZero() {
+		return time.Time{}
+	}
+
+	// 先按最大长度跳到t之前，再逐个前进。
+	i := 0
+	if longest := s.Period.longest(); longest > 0 && t.After(s.Start) {
+		i = int(t.Sub(s.Start) / longest)
+	}
+	for ; s.Repeat < 0 || i < s.Repeat; i++ {
+		if next := s.Period.AddTo(s.Start, i); next.After(t) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// String返回ISO 8601格式的重复时间间隔。
+func (s IntervalSchedule) String() string {
+	repeat := "R"
+	if s.Repeat >= 0 {
+		repeat += strconv.Itoa(s.Repeat)
+	}
+	return repeat + "/" + s.Start.Format(time.RFC3339Nano) + "/" + s.Period.String()
+}
+
+// isRepeatingInterval报告spec是否为ISO 8601的重复时间间隔（以"R/"或"R"加数字开头）。
+func isRepeatingInterval(spec string) bool {
+	return len(spec) > 1 && spec[0] == 'R' && (spec[1] == '/' || spec[1] >= '0' && spec[1] <= '9')
+}
+
+// ParseInterval解析ISO 8601格式的重复时间间隔：
+//   "R" [n] "/" start "/" duration
+//   "R" [n] "/" start "/" end
+// 其中n为激活的总次数，省略时表示不限。不带时区的start按time.Local解释。
+//
+// 启用了RepeatingInterval选项的Parser也接受这种格式的spec，
+// 因此使用这种Parser的Cron可以直接通过AddFunc添加。
+func ParseInterval(spec string) (IntervalSchedule, error) {
+	schedule, err := parseInterval(spec, time.Local)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = spec
+	}
+	return schedule, err
+}
+
+var intervalLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"20060102T150405Z07:00",
+	"20060102T150405",
+}
+
+// parseInterval实现了ParseInterval，不带时区的时间按loc解释。
+func parseInterval(spec string, loc *time.Location) (IntervalSchedule, error) {
+	var s IntervalSchedule
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 {
+		return s, atToken(parseErrorf(ReasonSyntax, "expected R[n]/start/duration: %s", spec), spec, 0)
+	}
+
+	s.Repeat = -1
+	if count := parts[0][1:]; count != "" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			return s, atToken(parseErrorf(ReasonOutOfRange, "repeat count must be a positive number: %s", parts[0]), parts[0], 0)
+		}
+		s.Repeat = n
+	}
+
+	startOffset := len(parts[0]) + 1
+	if strings.HasPrefix(parts[1], "P") {
+		return s, atToken(parseErrorf(ReasonUnsupported, "intervals must begin with a start time: %s", spec), parts[1], startOffset)
+	}
+	start, err := parseIntervalTime(parts[1], loc)
+	if err != nil {
+		return s, atToken(err, parts[1], startOffset)
+	}
+	s.Start = start
+
+	endOffset := startOffset + len(parts[1]) + 1
+	if strings.HasPrefix(parts[2], "P") {
+		s.Period, err = ParsePeriod(parts[2])
+	} else {
+		var end time.Time
+		if end, err = parseIntervalTime(parts[2], start.Location()); err == nil {
+			s.Period = Period{Duration: end.Sub(start)}
+		}
+	}
+	if err != nil {
+		return s, atToken(err, parts[2], endOffset)
+	}
+	if s.Period.longest() <= 0 {
+		return s, atToken(parseErrorf(ReasonOutOfRange, "interval must be positive: %s", parts[2]), parts[2], endOffset)
+	}
+	return s, nil
+}
+
+func parseIntervalTime(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range intervalLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, parseErrorf(ReasonSyntax, "failed to parse time %s", value)
+}
@@ -0,0 +1,154 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var intervalParser = NewParser(Minute | Hour | Dom | Month | Dow | Descriptor | RepeatingInterval)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected Period
+		str      string
+	}{
+		{"PT1H", Period{Duration: time.Hour}, "PT1H"},
+		{"PT1H30M", Period{Duration: 90 * time.Minute}, "PT1H30M"},
+		{"PT0.5S", Period{Duration: 500 * time.Millisecond}, "PT0.5S"},
+		{"PT1,5S", Period{Duration: 1500 * time.Millisecond}, "PT1.5S"},
+		{"P1M", Period{Months: 1}, "P1M"},
+		{"P2W", Period{Days: 14}, "P2W"},
+		{"P1Y2M3DT4H5M6S", Period{1, 2, 3, 4*time.Hour + 5*time.Minute + 6*time.Second}, "P1Y2M3DT4H5M6S"},
+		{"P1W2D", Period{Days: 9}, "P9D"},
+	}
+	for _, test := range tests {
+		actual, err := ParsePeriod(test.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.expr, err)
+			continue
+		}
+		if actual != test.expected {
+			t.Errorf("%s => expected %+v, got %+v", test.expr, test.expected, actual)
+		}
+		if actual.String() != test.str {
+			t.Errorf("%s => expected %q, got %q", test.expr, test.str, actual.String())
+		}
+	}
+
+	for _, expr := range []string{"", "P", "1H", "PT", "PH", "P1H", "PT1D", "P1M1Y", "P1.5D", "PT1H1H"} {
+		if _, err := ParsePeriod(expr); err == nil {
+			t.Errorf("%s => expected an error, got none", expr)
+		}
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	tests := []struct {
+		period   string
+		time     string
+		n        int
+		expected string
+	}{
+		{"P1M", "Tue Jan 31 09:00 2012", 1, "Wed Feb 29 09:00 2012"},
+		{"P1M", "Tue Jan 31 09:00 2012", 2, "Sat Mar 31 09:00 2012"},
+		{"P1Y", "Wed Feb 29 09:00 2012", 1, "Thu Feb 28 09:00 2013"},
+		{"P1W", "Mon Jul 9 09:00 2012", 3, "Mon Jul 30 09:00 2012"},
+		{"P1DT1H", "Mon Jul 9 09:00 2012", 2, "Wed Jul 11 11:00 2012"},
+
+		// Days keep the local time across daylight savings; hours do not.
+		{"P1D", "TZ=America/New_York 2012-03-10T09:00:00-0500", 1, "TZ=America/New_York 2012-03-11T09:00:00-0400"},
+		{"PT24H", "TZ=America/New_York 2012-03-10T09:00:00-0500", 1, "TZ=America/New_York 2012-03-11T10:00:00-0400"},
+	}
+	for _, test := range tests {
+		p, _ := ParsePeriod(test.period)
+		actual := p.AddTo(getTime(test.time), test.n)
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s + %d*%s => expected %v, got %v", test.time, test.n, test.period, expected, actual)
+		}
+	}
+}
+
+func TestIntervalNext(t *testing.T) {
+	tests := []struct {
+		spec     string
+		time     string
+		expected string
+	}{
+		{"R5/2012-07-09T00:00:00Z/PT1H", "TZ=UTC 2012-07-01T00:00:00-0000", "TZ=UTC 2012-07-09T00:00:00-0000"},
+		{"R5/2012-07-09T00:00:00Z/PT1H", "TZ=UTC 2012-07-09T00:00:00-0000", "TZ=UTC 2012-07-09T01:00:00-0000"},
+		{"R5/2012-07-09T00:00:00Z/PT1H", "TZ=UTC 2012-07-09T03:59:59-0000", "TZ=UTC 2012-07-09T04:00:00-0000"},
+		{"R5/2012-07-09T00:00:00Z/PT1H", "TZ=UTC 2012-07-09T04:00:00-0000", ""},
+		{"R/2012-07-09T00:15:00Z/PT1H", "TZ=UTC 2022-07-09T04:00:00-0000", "TZ=UTC 2022-07-09T04:15:00-0000"},
+		{"R/2012-01-31T09:00:00Z/P1M", "TZ=UTC 2112-02-01T00:00:00-0000", "TZ=UTC 2112-02-29T09:00:00-0000"},
+		{"R/2012-07-09T09:00:00Z/2012-07-09T09:45:00Z", "TZ=UTC 2012-07-09T10:00:00-0000", "TZ=UTC 2012-07-09T10:30:00-0000"},
+		{"R/2012-07-09T09:00:00-04:00/P1W", "TZ=UTC 2012-07-10T00:00:00-0000", "TZ=UTC 2012-07-16T13:00:00-0000"},
+
+		// Periods longer than a time.Duration can hold.
+		{"R/2026-01-01T00:00:00Z/P300Y", "TZ=UTC 2026-06-01T00:00:00-0000", "TZ=UTC 2326-01-01T00:00:00-0000"},
+		{"R/2026-01-01T00:00:00Z/P3600M", "TZ=UTC 2326-01-01T00:00:00-0000", "TZ=UTC 2626-01-01T00:00:00-0000"},
+		{"R/2026-01-01T00:00:00Z/P290Y100M", "TZ=UTC 2025-06-01T00:00:00-0000", "TZ=UTC 2026-01-01T00:00:00-0000"},
+	}
+	for _, test := range tests {
+		sched, err := intervalParser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		actual := sched.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, %s => expected %v, got %v", test.spec, test.time, expected, actual)
+		}
+	}
+
+	// Without an offset the start is interpreted in the spec's time zone.
+	sched, _ := intervalParser.Parse("CRON_TZ=Asia/Tokyo R/2012-07-09T09:00/P1D")
+	if actual, expected := sched.Next(getTime("TZ=UTC 2012-07-09T23:00:00-0000")), getTime("TZ=Asia/Tokyo 2012-07-10T09:00:00+0900"); !actual.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIntervalString(t *testing.T) {
+	for _, spec := range []string{"R5/2026-01-01T00:00:00Z/PT1H", "R/2026-01-31T09:00:00+09:00/P1M"} {
+		sched, err := ParseInterval(spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", spec, err)
+			continue
+		}
+		if actual := sched.String(); actual != spec {
+			t.Errorf("expected %q, got %q", spec, actual)
+		}
+	}
+}
+
+func TestParseIntervalErrors(t *testing.T) {
+	tests := []struct {
+		spec   string
+		offset int
+		reason ParseErrorReason
+	}{
+		{"R5/2026-01-01T00:00:00Z", 0, ReasonSyntax},
+		{"R0/2026-01-01T00:00:00Z/PT1H", 0, ReasonOutOfRange},
+		{"R5/PT1H/2026-01-01T00:00:00Z", 3, ReasonUnsupported},
+		{"R5/2026-13-01T00:00:00Z/PT1H", 3, ReasonSyntax},
+		{"R5/2026-01-01T00:00:00Z/PT1X", 24, ReasonSyntax},
+		{"R5/2026-01-01T00:00:00Z/PT0S", 24, ReasonOutOfRange},
+		{"R5/2026-01-01T00:00:00Z/2025-01-01T00:00:00Z", 24, ReasonOutOfRange},
+		{"TZ=UTC R5/2026-01-01T00:00:00Z/P", 31, ReasonSyntax},
+	}
+	for _, test := range tests {
+		_, err := intervalParser.Parse(test.spec)
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Offset != test.offset || perr.Reason != test.reason {
+			t.Errorf("%s => expected %s error at %d, got %+v", test.spec, test.reason, test.offset, err)
+		}
+	}
+
+	// Parsers without the option reject repeating intervals.
+	_, err := ParseStandard("R5/2026-01-01T00:00:00Z/PT1H")
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Reason != ReasonUnsupported {
+		t.Errorf("expected an unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package cron
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxActivations是Iterator（以及NextN和Between）最多计算的激活时间的个数，
+// 防止诸如"每秒一次、持续十年"这样的查询耗尽内存。
+const MaxActivations = 100000
+
+var (
+	// ErrNeverFires表示时间表在给定时间之后不会再被激活，
+	// 例如"0 0 30 2 *"（2月30日），或者年份已经全部过去的时间表。
+	ErrNeverFires = errors.New("cron: schedule never fires")
+
+	// ErrTooManyActivations表示迭代达到了MaxActivations的上限。
+	ErrTooManyActivations = errors.New("cron: too many activations")
+
+	// ErrNotAdvancing表示时间表的Next返回的时间没有晚于给定时间，继续迭代会陷入死循环。
+	ErrNotAdvancing = errors.New("cron: schedule did not advance")
+)
+
+// Iterator按时间顺序遍历任意Schedule的激活时间。
+//
+// 示例
+//
+//  it := cron.NewIterator(sched, time.Now())
+//  for it.Next() {
+//  	fmt.Println(it.Time())
+//  }
+//  if err := it.Err(); err != nil {
+//  	...
+//  }
+//
+type Iterator struct {
+	schedule Schedule
+	t        time.Time // 当前的激活时间（或起点）
+	end      time.Time // bounded为true时，只遍历不晚于end的激活时间
+	bounded  bool
+	count    int
+	err      error
+}
+
+// NewIterator返回一个从from之后（不包括from）开始遍历schedule激活时间的Iterator。
+// 遍历在时间表不再激活、或达到MaxActivations时结束。
+func NewIterator(schedule Schedule, from time.Time) *Iterator {
+	return &Iterator{schedule: schedule, t: from}
+}
+
+// Next前进到下一个激活时间，如果遍历已经结束，则返回false。
+func (it *Iterator) Next() bool {
+	if it.err != nil || it.schedule == nil {
+		return false
+	}
+	if it.count >= MaxActivations {
+		it.err = ErrTooManyActivations
+		return false
+	}
+
+	next := it.schedule.Next(it.t)
+	switch {
+	case next.IsZero():
+		// 时间表已经结束；只有从未激活过时才报告为错误。
+		if it.count == 0 {
+			it.err = ErrNeverFires
+		}
+		it.schedule = nil
+		return false
+	case !next.After(it.t):
+		it.err = ErrNotAdvancing
+		return false
+	case it.bounded && next.After(it.end):
+		it.schedule = nil
+		return false
+	}
+
+	it.t = next
+	it.count++
+	return true
+}
+
+// Time返回当前的激活时间，只有在Next返回true之后才有意义。
+func (it *Iterator) Time() time.Time {
+	return it.t
+}
+
+// Err返回导致遍历提前结束的错误；正常结束时返回nil。
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// NextN返回schedule在t之后的前n个激活时间。
+// 如果时间表提前结束，则返回的时间少于n个；如果从未激活，则返回ErrNeverFires。
+func NextN(schedule Schedule, t time.Time, n int) ([]time.Time, error) {
+	var times []time.Time
+	it := NewIterator(schedule, t)
+	for len(times) < n && it.Next() {
+		times = append(times, it.Time())
+	}
+	return times, it.Err()
+}
+
+// Between返回schedule晚于from且不晚于to的所有激活时间。
+// 如果这段时间内没有激活时间，则返回空切片；只有时间表在from之后永远不会激活时才返回ErrNeverFires。
+func Between(schedule Schedule, from, to time.Time) ([]time.Time, error) {
+	var times []time.Time
+	if to.Before(from) {
+		return times, nil
+	}
+	it := NewIterator(schedule, from)
+	it.end, it.bounded = to, true
+	for it.Next() {
+		times = append(times, it.Time())
+	}
+	return times, it.Err()
+}
@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNextN(t *testing.T) {
+	yearParser := NewParser(Minute | Hour | Dom | Month | Dow | YearOptional)
+	tests := []struct {
+		parser   Parser
+		spec     string
+		from     string
+		n        int
+		expected []string
+		err      error
+	}{
+		{standardParser, "0 */6 * * *", "Mon Jul 9 14:45 2012", 3,
+			[]string{"Mon Jul 9 18:00 2012", "Tue Jul 10 00:00 2012", "Tue Jul 10 06:00 2012"}, nil},
+		{standardParser, "@every 90m", "Mon Jul 9 14:45 2012", 2,
+			[]string{"Mon Jul 9 16:15 2012", "Mon Jul 9 17:45 2012"}, nil},
+		{standardParser, "0 0 1 1 *", "Mon Jul 9 14:45 2012", 0, nil, nil},
+
+		// Ends early, without an error, once the years run out
+		{yearParser, "0 0 1 1 * 2013-2014", "Mon Jul 9 14:45 2012", 3,
+			[]string{"Tue Jan 1 00:00 2013", "Wed Jan 1 00:00 2014"}, nil},
+
+		// Schedules that never fire
+		{standardParser, "0 0 30 2 *", "Mon Jul 9 14:45 2012", 3, nil, ErrNeverFires},
+		{yearParser, "0 0 1 1 * 2011", "Mon Jul 9 14:45 2012", 3, nil, ErrNeverFires},
+	}
+
+	for _, test := range tests {
+		sched, err := test.parser.Parse(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := NextN(sched, getTime(test.from), test.n)
+		if err != test.err {
+			t.Errorf("%s => expected error %v, got %v", test.spec, test.err, err)
+		}
+		if !reflect.DeepEqual(actual, getTimes(test.expected)) {
+			t.Errorf("%s => expected %v, got %v", test.spec, test.expected, actual)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	sched, _ := ParseStandard("0 */6 * * *")
+
+	// Excludes from, includes to
+	actual, err := Between(sched, getTime("Mon Jul 9 00:00 2012"), getTime("Tue Jul 10 00:00 2012"))
+	expected := getTimes([]string{
+		"Mon Jul 9 06:00 2012",
+		"Mon Jul 9 12:00 2012",
+		"Mon Jul 9 18:00 2012",
+		"Tue Jul 10 00:00 2012",
+	})
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v (%v)", expected, actual, err)
+	}
+
+	// Windows without activations
+	actual, err = Between(sched, getTime("Mon Jul 9 01:00 2012"), getTime("Mon Jul 9 05:00 2012"))
+	if err != nil || len(actual) != 0 {
+		t.Errorf("expected no activations, got %v (%v)", actual, err)
+	}
+	actual, err = Between(sched, getTime("Tue Jul 10 00:00 2012"), getTime("Mon Jul 9 00:00 2012"))
+	if err != nil || len(actual) != 0 {
+		t.Errorf("expected no activations, got %v (%v)", actual, err)
+	}
+
+	never, _ := ParseStandard("0 0 31 4 *")
+	if _, err := Between(never, getTime("Mon Jul 9 00:00 2012"), getTime("Tue Jul 10 00:00 2012")); err != ErrNeverFires {
+		t.Errorf("expected ErrNeverFires, got %v", err)
+	}
+}
+
+func TestIteratorLimits(t *testing.T) {
+	every, _ := secondParser.Parse("* * * * * *")
+	from := getTime("Mon Jul 9 00:00 2012")
+	actual, err := Between(every, from, from.AddDate(1, 0, 0))
+	if err != ErrTooManyActivations {
+		t.Errorf("expected ErrTooManyActivations, got %v", err)
+	}
+	if len(actual) != MaxActivations {
+		t.Errorf("expected %d activations, got %d", MaxActivations, len(actual))
+	}
+
+	it := NewIterator(stuckSchedule{}, from)
+	if it.Next() {
+		t.Error("expected iteration to stop")
+	}
+	if it.Err() != ErrNotAdvancing {
+		t.Errorf("expected ErrNotAdvancing, got %v", it.Err())
+	}
+}
+
+// stuckSchedule always returns the given time.
+type stuckSchedule struct{}
+
+func (stuckSchedule) Next(t time.Time) time.Time { return t }
+
+func getTimes(values []string) []time.Time {
+	var times []time.Time
+	for _, value := range values {
+		times = append(times, getTime(value))
+	}
+	return times
+}
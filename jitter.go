@@ -0,0 +1,89 @@
+package cron
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// JitterSchedule将另一个时间表的每次激活推迟一个[0，Max)内的随机时长，
+// 类似于systemd的RandomizedDelaySec，用于避免大量作业同时访问共享的资源。
+//
+// 推迟的时长由Seed和基础激活时间确定，因此同一次激活总是得到相同的时间，
+// Next可以像其他时间表一样反复调用。推迟后的时间总是早于下一个基础激活时间，
+// 因此激活的顺序不会改变。
+type JitterSchedule struct {
+	Schedule Schedule
+	Max      time.Duration
+	Seed     int64
+}
+
+// Jitter返回将schedule的每次激活随机推迟最多max的时间表。
+// 随机数的种子从source中取得；source为nil时使用当前时间，
+// 测试中可以传入rand.NewSource(n)得到确定的结果。
+//
+// 示例
+//
+//  // 每小时运行一次，在整点之后的5分钟内随机开始
+//  sched, _ := cron.ParseStandard("@hourly")
+//  c.Schedule(cron.Jitter(sched, 5*time.Minute, nil), job)
+//
+func Jitter(schedule Schedule, max time.Duration, source rand.Source) JitterSchedule {
+	if source == nil {
+		source = rand.NewSource(time.Now().UnixNano())
+	}
+	return JitterSchedule{Schedule: schedule, Max: max, Seed: source.Int63()}
+}
+
+// Next返回推迟后晚于t的最早激活时间。如果基础时间表不再激活，则返回时间的零值。
+func (s JitterSchedule) Next(t time.Time) time.Time {
+	if s.Max <= 0 {
+		return s.Schedule.Next(t)
+	}
+
+	// 只有不晚于t的最后一个基础激活时间推迟后可能晚于t，更早的都会在它之前。
+	// 从给定时间算起的时间表（例如"@every"）的Prev在t之前一个间隔，推迟后不会晚于t，
+	// 因此它们直接推迟下一次激活。
+	if prev, ok := s.Schedule.(PrevSchedule); ok {
+		if base := prev.Prev(t); !base.IsZero() {
+			if next := base.Add(s.delay(base, s.Schedule.Next(base))); next.After(t) {
+				return next
+			}
+		}
+		base := s.Schedule.Next(t)
+		if base.IsZero() {
+			return base
+		}
+		return base.Add(s.delay(base, s.Schedule.Next(base)))
+	}
+
+	// 没有Prev时，从t-Max之后的基础激活时间开始查找：更早的推迟后也不会晚于t。
+	base := s.Schedule.Next(t.Add(-s.Max))
+	for attempts := 0; attempts < MaxActivations && !base.IsZero(); attempts++ {
+		following := s.Schedule.Next(base)
+		if next := base.Add(s.delay(base, following)); next.After(t) {
+			return next
+		}
+		base = following
+	}
+	return time.Time{}
+}
+
+// delay返回基础激活时间base的推迟时长：在[0，Max)内，并且小于到下一个基础激活时间following的间隔。
+func (s JitterSchedule) delay(base, following time.Time) time.Duration {
+	window := s.Max
+	if gap := following.Sub(base); !following.IsZero() && gap < window {
+		window = gap
+	}
+	if window <= 0 {
+		return 0
+	}
+
+	var b [16]byte
+	binary.LittleEndian.PutUint64(b[:8], uint64(s.Seed))
+	binary.LittleEndian.PutUint64(b[8:], uint64(base.UnixNano()))
+	h := fnv.New64a()
+	h.Write(b[:])
+	return time.Duration(h.Sum64() % uint64(window))
+}
@@ -0,0 +1,148 @@
+package cron
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestJitterStaysBetweenBaseActivations(t *testing.T) {
+	tests := []struct {
+		spec string
+		max  time.Duration
+	}{
+		{"@hourly", 5 * time.Minute},
+		{"* * * * *", time.Hour},
+		{"0 9 * * mon-fri", 12 * time.Hour},
+		{"@every 10m from 00:05", 5 * time.Minute},
+	}
+	start := getTime("Mon Jul 9 14:45 2012")
+	for _, test := range tests {
+		base, err := ParseStandard(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jitter := Jitter(base, test.max, rand.NewSource(1))
+
+		var (
+			b    = base.Next(start.Add(-test.max))
+			prev = start
+			seen = map[time.Duration]bool{}
+		)
+		for i, next := 0, jitter.Next(start); i < 50; i, next = i+1, jitter.Next(next) {
+			if !next.After(prev) {
+				t.Fatalf("%s: activation %d at %v is not after %v", test.spec, i, next, prev)
+			}
+
+			// Each activation must come from a distinct base activation, in order.
+			for following := base.Next(b); !following.After(next); following = base.Next(b) {
+				b = following
+			}
+			following := base.Next(b)
+			if i > 0 && !b.After(prev) || next.Sub(b) >= test.max {
+				t.Fatalf("%s: activation %d at %v, expected within [%v, %v) and under %v", test.spec, i, next, b, following, test.max)
+			}
+			seen[next.Sub(b)] = true
+			prev = next
+		}
+		if len(seen) < 10 {
+			t.Errorf("%s: expected varied delays, got %v", test.spec, seen)
+		}
+	}
+}
+
+func TestJitterIsDeterministic(t *testing.T) {
+	base, _ := ParseStandard("@hourly")
+	start := getTime("Mon Jul 9 14:45 2012")
+
+	a, _ := NextN(Jitter(base, 30*time.Minute, rand.NewSource(42)), start, 20)
+	b, _ := NextN(Jitter(base, 30*time.Minute, rand.NewSource(42)), start, 20)
+	c, _ := NextN(Jitter(base, 30*time.Minute, rand.NewSource(43)), start, 20)
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			t.Errorf("activation %d: %v != %v with the same seed", i, a[i], b[i])
+		}
+	}
+	same := 0
+	for i := range a {
+		if a[i].Equal(c[i]) {
+			same++
+		}
+	}
+	if same == len(a) {
+		t.Error("expected different seeds to give different activations")
+	}
+
+	// Asking again from any time before an activation finds the same activation.
+	jitter := Jitter(base, 30*time.Minute, rand.NewSource(42))
+	for _, next := range a {
+		if actual := jitter.Next(next.Add(-time.Nanosecond)); !actual.Equal(next) {
+			t.Errorf("expected %v, got %v", next, actual)
+		}
+		if hour := next.Truncate(time.Hour); !hour.Equal(next) {
+			if actual := jitter.Next(hour); !actual.Equal(next) {
+				t.Errorf("from %v: expected %v, got %v", hour, next, actual)
+			}
+		}
+	}
+}
+
+func TestJitterEdgeCases(t *testing.T) {
+	base, _ := ParseStandard("@hourly")
+	start := getTime("Mon Jul 9 14:45 2012")
+
+	if actual := Jitter(base, 0, nil).Next(start); !actual.Equal(base.Next(start)) {
+		t.Errorf("expected no jitter without a maximum, got %v", actual)
+	}
+	never, _ := ParseStandard("0 0 30 2 *")
+	if actual := Jitter(never, time.Minute, nil).Next(start); !actual.IsZero() {
+		t.Errorf("expected the zero time, got %v", actual)
+	}
+
+	// Schedules relative to the given time are delayed after each interval,
+	// rather than having their intervals shortened.
+	for _, sched := range []Schedule{Every(90 * time.Second), relativeSchedule{90 * time.Second}} {
+		every := Jitter(sched, 10*time.Second, rand.NewSource(1))
+		for i, prev := 0, start; i < 20; i++ {
+			next := every.Next(prev)
+			if d := next.Sub(prev); d < 90*time.Second || d >= 100*time.Second {
+				t.Fatalf("%T: activation %d: expected 90s to 100s after %v, got %v", sched, i, prev, next)
+			}
+			prev = next.Truncate(time.Second)
+		}
+	}
+
+	// Anchored schedules have fixed activations, so asking again from before a
+	// delayed activation finds the same one.
+	anchored := Jitter(EveryFrom(10*time.Minute, start), 5*time.Minute, rand.NewSource(1))
+	for i, prev := 0, start; i < 20; i++ {
+		next := anchored.Next(prev)
+		if again := anchored.Next(next.Add(-time.Second)); !again.Equal(next) {
+			t.Fatalf("activation %d: expected %v again, got %v", i, next, again)
+		}
+		prev = next
+	}
+
+	// The last activation may use the full window.
+	once, err := NewParser(Minute | Hour | Dom | Month | Dow | Year).Parse("0 0 1 1 * 2013")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jitter := Jitter(once, time.Hour, rand.NewSource(1))
+	next := jitter.Next(start)
+	if next.Before(getTime("Tue Jan 1 00:00 2013")) || !next.Before(getTime("Tue Jan 1 01:00 2013")) {
+		t.Errorf("expected an activation in the first hour of 2013, got %v", next)
+	}
+	if after := jitter.Next(next); !after.IsZero() {
+		t.Errorf("expected no further activations, got %v", after)
+	}
+}
+
+// relativeSchedule activates a fixed delay after the given time, like Every
+// without rounding to the second.
+type relativeSchedule struct {
+	delay time.Duration
+}
+
+func (s relativeSchedule) Next(t time.Time) time.Time { return t.Add(s.delay) }
+func (s relativeSchedule) Prev(t time.Time) time.Time { return t.Add(-s.delay) }
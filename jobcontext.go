@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// JobWithContext是接收上下文的作业，由AddJobCtx提交。
+// 上下文在Cron停止或条目被删除时取消，长时间运行的作业应当据此尽快返回；
+// 上下文中的运行信息可以通过RunInfoFromContext获取。返回的错误会记录到Cron的日志器中。
+type JobWithContext interface {
+	Run(ctx context.Context) error
+}
+
+// FuncJobCtx是一个包装器，将函数func(context.Context) error变成JobWithContext。
+type FuncJobCtx func(ctx context.Context) error
+
+func (f FuncJobCtx) Run(ctx context.Context) error { return f(ctx) }
+
+// RunInfo描述了JobWithContext的一次运行。
+type RunInfo struct {
+	// EntryID是作业所在条目的ID。
+	EntryID EntryID
+
+	// Scheduled是本次运行对应的激活时间。
+	Scheduled time.Time
+
+	// Started是作业实际开始运行的时间，可能因唤醒的延迟或DelayIfStillRunning而晚于Scheduled。
+	Started time.Time
+}
+
+type runInfoKey struct{}
+
+// RunInfoFromContext返回ctx中的运行信息；ctx不是Cron传给JobWithContext的上下文时返回false。
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
+
+// AddFuncCtx增加一个接收上下文的函数到Cron上，在给定的时间表中运行。
+// spec使用Cron实例默认的时区进行解析。
+// 返回一个不透明的ID，可用于以后将其删除。
+func (c *Cron) AddFuncCtx(spec string, cmd func(ctx context.Context) error) (EntryID, error) {
+	return c.AddJobCtx(spec, FuncJobCtx(cmd))
+}
+
+// AddJobCtx将接收上下文的作业添加到Cron中，以便按给定的时间表运行。
+// spec使用Cron实例默认的时区进行解析。
+// 返回一个不透明的ID，可用于以后将其删除。
+func (c *Cron) AddJobCtx(spec string, cmd JobWithContext) (EntryID, error) {
+	schedule, err := c.parser.Parse(spec)
+	if err != nil {
+		return 0, err
+	}
+	return c.ScheduleCtx(schedule, cmd), nil
+}
+
+// ScheduleCtx将接收上下文的作业添加到Cron中，以便按给定的时间表运行。
+// 该作业由配置的Chain包裹。
+func (c *Cron) ScheduleCtx(schedule Schedule, cmd JobWithContext) EntryID {
+	return c.schedule(schedule, nil, cmd)
+}
+
+// contextJob将JobWithContext适配为Job，使其可以由Chain包裹。
+// 由于Job.Run没有参数，每次激活的时间由activation返回的作业交给Run。
+type contextJob struct {
+	job  JobWithContext
+	id   EntryID
+	cron *Cron
+
+	// handoff保证同一时刻最多只有一次激活把激活时间放在pending中，等待它的Run取走。
+	handoff chan struct{}
+
+	mu      sync.Mutex
+	ctx     context.Context    // 条目在本次启动期间的上下文，Stop或删除条目时取消
+	cancel  context.CancelFunc // 取消ctx
+	pending time.Time          // 持有handoff的激活的时间，Run取走后保留为最近一次的激活时间
+	waiting uint64             // 等待Run取走pending的激活的序号，0表示没有
+	seq     uint64             // 最近一次激活的序号
+}
+
+// activation返回在激活时间scheduled运行wrapped（由Chain包裹的本作业）的作业。
+// Chain中的包装器在同一个goroutine中同步调用内层的作业，而在Run取走激活时间之前，
+// 其他激活都在等待handoff，因此Run得到的总是它自己那次激活的时间，即使运行被
+// DelayIfStillRunning推迟或与其他运行重叠。被SkipIfStillRunning跳过时，运行结束后收回激活时间。
+func (j *contextJob) activation(wrapped Job, scheduled time.Time) Job {
+	return FuncJob(func() {
+		j.handoff <- struct{}{}
+		j.mu.Lock()
+		j.seq++
+		seq := j.seq
+		j.pending, j.waiting = scheduled, seq
+		j.mu.Unlock()
+
+		defer func() {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			if j.waiting == seq {
+				j.waiting = 0
+				<-j.handoff
+			}
+		}()
+		wrapped.Run()
+	})
+}
+
+// Run以记录的上下文运行作业，并记录作业返回的错误。
+// 不是由activation调用时（例如包装器重复调用），得到的是最近一次激活的时间。
+func (j *contextJob) Run() {
+	j.mu.Lock()
+	parent, scheduled := j.ctx, j.pending
+	if j.waiting != 0 {
+		j.waiting = 0
+		<-j.handoff
+	}
+	j.mu.Unlock()
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx := context.WithValue(parent, runInfoKey{}, RunInfo{j.id, scheduled, j.cron.now()})
+	if err := j.job.Run(ctx); err != nil {
+		j.cron.logger.Error(err, "job failed", "entry", j.id, "scheduled", scheduled)
+	}
+}
+
+// start在调度程序启动或条目加入运行中的调度程序时，从parent派生条目的上下文。
+func (j *contextJob) start(parent context.Context) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ctx, j.cancel = context.WithCancel(parent)
+}
+
+// stop在条目被删除时取消条目的上下文。
+func (j *contextJob) stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.cancel != nil {
+		j.cancel()
+	}
+}
@@ -0,0 +1,274 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddFuncCtx(t *testing.T) {
+	infos := make(chan RunInfo, 1)
+	cron := newWithSeconds()
+	id, err := cron.AddFuncCtx("* * * * * ?", func(ctx context.Context) error {
+		info, ok := RunInfoFromContext(ctx)
+		if !ok {
+			t.Error("expected run info in the context")
+		}
+		select {
+		case infos <- info:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case info := <-infos:
+		if info.EntryID != id {
+			t.Errorf("expected entry %d, got %d", id, info.EntryID)
+		}
+		if info.Scheduled.IsZero() || info.Scheduled.Nanosecond() != 0 {
+			t.Errorf("expected a whole second, got %v", info.Scheduled)
+		}
+		if info.Started.Before(info.Scheduled) {
+			t.Errorf("expected the job to start after %v, got %v", info.Scheduled, info.Started)
+		}
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to run")
+	}
+
+	entry := cron.Entry(id)
+	if _, ok := entry.JobWithContext.(FuncJobCtx); !ok {
+		t.Errorf("expected the submitted job in the entry, got %v", entry.JobWithContext)
+	}
+
+	if _, err := cron.AddFuncCtx("this will not parse", nil); err == nil {
+		t.Error("expected an error with invalid spec, got nil")
+	}
+}
+
+func TestJobWithContextCancelledOnStop(t *testing.T) {
+	started, done := make(chan struct{}), make(chan error, 1)
+	cron := newWithSeconds()
+	cron.AddFuncCtx("* * * * * ?", func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+			return nil
+		}
+		<-ctx.Done()
+		done <- ctx.Err()
+		return nil
+	})
+	cron.Start()
+
+	select {
+	case <-started:
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to run")
+	}
+	ctx := cron.Stop()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the job's context to be cancelled")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("expected Stop to wait only for the cancelled job")
+	}
+}
+
+func TestJobWithContextCancelledOnRemove(t *testing.T) {
+	started, done := make(chan struct{}), make(chan error, 1)
+	other := make(chan struct{}, 10)
+	cron := newWithSeconds()
+	id, _ := cron.AddFuncCtx("* * * * * ?", func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+			return nil
+		}
+		<-ctx.Done()
+		done <- ctx.Err()
+		return nil
+	})
+	cron.AddFuncCtx("* * * * * ?", func(context.Context) error {
+		other <- struct{}{}
+		return nil
+	})
+	cron.Start()
+	defer cron.Stop()
+
+	select {
+	case <-started:
+	case <-time.After(OneSecond):
+		t.Fatal("expected the job to run")
+	}
+	cron.Remove(id)
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the removed job's context to be cancelled")
+	}
+
+	// The other entry keeps running with a live context.
+	for len(other) > 0 {
+		<-other
+	}
+	select {
+	case <-other:
+	case <-time.After(OneSecond):
+		t.Error("expected the other job to keep running")
+	}
+}
+
+func TestJobWithContextRestart(t *testing.T) {
+	errs := make(chan error, 10)
+	cron := newWithSeconds()
+	cron.AddFuncCtx("* * * * * ?", func(ctx context.Context) error {
+		errs <- ctx.Err()
+		return nil
+	})
+
+	// Each Start gives the entry a fresh context.
+	for i := 0; i < 2; i++ {
+		cron.Start()
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Errorf("start %d: expected a live context, got %v", i, err)
+			}
+		case <-time.After(OneSecond):
+			t.Fatalf("start %d: expected the job to run", i)
+		}
+		<-cron.Stop().Done()
+		for len(errs) > 0 {
+			<-errs
+		}
+	}
+}
+
+func TestJobWithContextScheduledPerRun(t *testing.T) {
+	times := []time.Time{
+		getTime("2012-07-09T10:00:00-0000"),
+		getTime("2012-07-09T10:00:01-0000"),
+		getTime("2012-07-09T10:00:02-0000"),
+	}
+	chains := map[string][]JobWrapper{
+		"overlapping": nil,
+		"delayed":     {DelayIfStillRunning(DiscardLogger)},
+	}
+	for name, wrappers := range chains {
+		var (
+			release = make(chan struct{})
+			got     = make(chan time.Time, len(times))
+			cron    = New(WithChain(wrappers...))
+		)
+		cron.ScheduleCtx(Every(time.Hour), FuncJobCtx(func(ctx context.Context) error {
+			info, _ := RunInfoFromContext(ctx)
+			got <- info.Scheduled
+			<-release
+			return nil
+		}))
+
+		// Start every activation before the first one finishes.
+		for _, scheduled := range times {
+			cron.startJob(cron.entries[0], scheduled)
+		}
+		seen := map[time.Time]bool{<-got: true}
+		close(release)
+		cron.jobWaiter.Wait()
+
+		// Each run reports its own activation, not the most recent one.
+		for i := 1; i < len(times); i++ {
+			seen[<-got] = true
+		}
+		if len(seen) != len(times) {
+			t.Errorf("%s: expected distinct activation times, got %v", name, seen)
+		}
+	}
+}
+
+func TestJobWithContextScheduledAfterSkip(t *testing.T) {
+	var (
+		buf     syncWriter
+		release = make(chan struct{})
+		got     = make(chan time.Time, 3)
+		cron    = New(WithChain(SkipIfStillRunning(VerbosePrintfLogger(log.New(&buf, "", 0)))))
+		t1      = getTime("2012-07-09T10:00:00-0000")
+		t3      = getTime("2012-07-09T10:00:02-0000")
+	)
+	cron.ScheduleCtx(Every(time.Hour), FuncJobCtx(func(ctx context.Context) error {
+		info, _ := RunInfoFromContext(ctx)
+		got <- info.Scheduled
+		<-release
+		return nil
+	}))
+	entry := cron.entries[0]
+
+	cron.startJob(entry, t1)
+	if actual := <-got; !actual.Equal(t1) {
+		t.Errorf("expected %v, got %v", t1, actual)
+	}
+	cron.startJob(entry, t1.Add(time.Second))
+	for deadline := time.Now().Add(time.Second); !strings.Contains(buf.String(), "skip"); {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the second activation to be skipped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	cron.jobWaiter.Wait()
+
+	// The skipped activation's time is not handed to the next run.
+	cron.startJob(entry, t3)
+	cron.jobWaiter.Wait()
+	if actual := <-got; !actual.Equal(t3) {
+		t.Errorf("expected %v, got %v", t3, actual)
+	}
+}
+
+func TestJobWithContextError(t *testing.T) {
+	var buf syncWriter
+	cron := New(WithParser(secondParser), WithLogger(newBufLogger(&buf)))
+	cron.AddFuncCtx("* * * * * ?", func(context.Context) error {
+		return errors.New("disk full")
+	})
+	cron.Start()
+	time.Sleep(OneSecond)
+	<-cron.Stop().Done()
+	if out := buf.String(); !strings.Contains(out, "job failed") || !strings.Contains(out, "disk full") {
+		t.Errorf("expected the error to be logged, got %q", out)
+	}
+}
+
+func TestJobWithContextChain(t *testing.T) {
+	var buf syncWriter
+	cron := New(
+		WithParser(secondParser),
+		WithChain(Recover(newBufLogger(&buf))))
+	cron.AddFuncCtx("* * * * * ?", func(context.Context) error {
+		panic("YOLO")
+	})
+	cron.Start()
+	time.Sleep(OneSecond)
+	<-cron.Stop().Done()
+	if out := buf.String(); !strings.Contains(out, "YOLO") {
+		t.Error("expected the job to be wrapped by the chain")
+	}
+}
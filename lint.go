@@ -0,0 +1,215 @@
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WarningKind是Lint警告的种类，可供程序判断。
+type WarningKind int
+
+const (
+	WarnNeverFires     WarningKind = iota + 1 // 时间表不会再激活，例如"0 0 31 2 *"
+	WarnEveryMinute                           // 在限定的时间内每分钟都激活，例如"* 3 * * *"
+	WarnEverySecond                           // 在限定的时间内每秒都激活，例如"* 0 3 * * *"
+	WarnDomDowOr                              // 同时限制了日期和星期，二者满足其一即可激活
+	WarnDST                                   // 激活时间因夏令时转换而不存在或重复
+	WarnRedundantRange                        // 字段中的某一项已被其他项覆盖，或覆盖了整个范围
+)
+
+var warningKindNames = map[WarningKind]string{
+	WarnNeverFires:     "never fires",
+	WarnEveryMinute:    "every minute",
+	WarnEverySecond:    "every second",
+	WarnDomDowOr:       "day-of-month or day-of-week",
+	WarnDST:            "daylight saving time",
+	WarnRedundantRange: "redundant range",
+}
+
+func (k WarningKind) String() string {
+	if name, ok := warningKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("WarningKind(%d)", int(k))
+}
+
+// Warning是Lint发现的可疑之处。时间表仍然有效，但可能与编写者的意图不同。
+type Warning struct {
+	// Kind是警告的种类。
+	Kind WarningKind
+
+	// Field是相关字段的名称，例如"minute"；与具体字段无关时为空。
+	Field string
+
+	// Msg是对警告的描述。
+	Msg string
+}
+
+func (w Warning) String() string {
+	if w.Field != "" {
+		return w.Field + " field: " + w.Msg
+	}
+	return w.Msg
+}
+
+// Lint解析spec，并返回对结果的警告（见SpecSchedule.Lint），以及spec中冗余的范围。
+// spec无效时返回解析错误。对于不是SpecSchedule的时间表（例如"@every 1h"），不返回警告。
+func (p Parser) Lint(spec string) ([]Warning, error) {
+	schedule, err := p.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := schedule.(*SpecSchedule)
+	if !ok {
+		return nil, nil
+	}
+	return append(p.lintFields(spec), s.Lint()...), nil
+}
+
+// lintFields返回spec的各个字段中冗余的项。描述符等不是由字段组成的spec没有警告。
+func (p Parser) lintFields(spec string) []Warning {
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.IndexAny(spec, " \t")
+		if i < 0 {
+			return nil
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+	if strings.HasPrefix(spec, "@") || isRRule(spec) || isRepeatingInterval(spec) {
+		return nil
+	}
+	fields, _ := splitFields(spec)
+	indexes, err := fieldIndexes(len(fields), p.options)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		warnings []Warning
+		ranges   = []bounds{seconds, minutes, hours, dom, months, dow}
+	)
+	for place, r := range ranges {
+		if indexes[place] < 0 {
+			continue
+		}
+		items := strings.Split(fields[indexes[place]], ",")
+		bits := make([]uint64, len(items))
+		for i, item := range items {
+			// 无法单独计算的项（例如修饰符和H表达式）不参与检查。
+			if bits[i], err = getRange(item, r, nil); err != nil {
+				bits[i] = 0
+			}
+			bits[i] &^= starBit
+		}
+		for i, item := range items {
+			if bits[i] == 0 {
+				continue
+			}
+			if item != "*" && item != "?" && bits[i] == all(r)&^starBit {
+				warnings = append(warnings, Warning{WarnRedundantRange, fieldNames[place],
+					fmt.Sprintf("%s matches every %s", item, fieldNames[place])})
+				continue
+			}
+			var others uint64
+			for j := range items {
+				if j != i && (bits[j] != bits[i] || j < i) {
+					others |= bits[j]
+				}
+			}
+			if bits[i]&^others == 0 {
+				warnings = append(warnings, Warning{WarnRedundantRange, fieldNames[place],
+					fmt.Sprintf("%s is already covered by the rest of the field", item)})
+			}
+		}
+	}
+	return warnings
+}
+
+// Lint返回对时间表的警告：不会再激活、在限定的时间内每分钟或每秒激活、
+// 日期和星期按"或"组合、以及在未来一年内因时区的夏令时转换而跳过或重复的激活时间。
+// 已由DST策略处理的转换（DSTNextValid或DSTOnce）不再警告。
+func (s *SpecSchedule) Lint() []Warning {
+	return s.lint(time.Now())
+}
+
+// lint实现了Lint，now是检查的起点。
+func (s *SpecSchedule) lint(now time.Time) []Warning {
+	var warnings []Warning
+	if s.Next(now).IsZero() {
+		warnings = append(warnings, Warning{WarnNeverFires, "", "schedule never fires"})
+	}
+
+	restricted := func(bits uint64, r bounds) bool {
+		return bits&starBit == 0 && bits != all(r)&^starBit
+	}
+	days := restricted(s.Dom, dom) || restricted(s.Dow, dow) || s.Month&starBit == 0 || s.Year != nil
+	switch {
+	case s.Second&^starBit == all(seconds)&^starBit && (restricted(s.Minute, minutes) || restricted(s.Hour, hours) || days):
+		warnings = append(warnings, Warning{WarnEverySecond, fieldNames[0],
+			"runs every second while the other fields match; use 0 to run once"})
+	case s.Minute&^starBit == all(minutes)&^starBit && (restricted(s.Hour, hours) || days):
+		warnings = append(warnings, Warning{WarnEveryMinute, fieldNames[1],
+			"runs every minute while the other fields match; use 0 to run once"})
+	}
+
+	if !s.DomAndDow && s.Dom&starBit == 0 && s.Dow&starBit == 0 {
+		warnings = append(warnings, Warning{WarnDomDowOr, "",
+			"runs on days matching either the day of month or the day of week; use the DayAnd option to require both"})
+	}
+
+	// 每小时都激活的时间表本来就不依赖于具体的时刻，不因夏令时警告。
+	if s.Hour&starBit == 0 {
+		warnings = append(warnings, s.lintDST(now)...)
+	}
+	return warnings
+}
+
+// lintDST返回时间表在now之后一年内因夏令时转换而跳过或重复的第一个激活时间。
+func (s *SpecSchedule) lintDST(now time.Time) []Warning {
+	loc := s.zone(now)
+	wallClock := *s
+	wallClock.Location = time.UTC
+
+	var (
+		warnings             []Warning
+		gapSeen, overlapSeen = s.DST&DSTNextValid > 0, s.DST&DSTOnce > 0
+		end                  = now.AddDate(1, 0, 0)
+		_, offset            = now.In(loc).Zone()
+	)
+	for t := now; t.Before(end) && !(gapSeen && overlapSeen); t = t.Add(time.Hour) {
+		_, next := t.Add(time.Hour).In(loc).Zone()
+		if next == offset {
+			continue
+		}
+		at := transition(t, t.Add(time.Hour), loc).Unix()
+		before, after := offset, next
+		offset = next
+
+		// 跳过的本地时间为[at+before, at+after)，重复的为[at+after, at+before)。
+		from, to := time.Unix(at+int64(before), 0).UTC(), time.Unix(at+int64(after), 0).UTC()
+		gap := after > before
+		if !gap {
+			from, to = to, from
+		}
+		if gap && gapSeen || !gap && overlapSeen {
+			continue
+		}
+		match := wallClock.nextMatch(from.Add(-time.Nanosecond))
+		if match.IsZero() || !match.Before(to) {
+			continue
+		}
+		if gap {
+			gapSeen = true
+			warnings = append(warnings, Warning{WarnDST, "", fmt.Sprintf(
+				"%s on %s does not exist in %s because of daylight saving time, so that activation is skipped",
+				match.Format("15:04:05"), match.Format("2006-01-02"), loc)})
+		} else {
+			overlapSeen = true
+			warnings = append(warnings, Warning{WarnDST, "", fmt.Sprintf(
+				"%s on %s occurs twice in %s because of daylight saving time, so the schedule fires twice",
+				match.Format("15:04:05"), match.Format("2006-01-02"), loc)})
+		}
+	}
+	return warnings
+}
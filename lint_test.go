@@ -0,0 +1,148 @@
+package cron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLint(t *testing.T) {
+	var (
+		quartz = NewParser(Minute | Hour | Dom | Month | Dow | QuartzModifiers)
+		and    = NewParser(Minute | Hour | Dom | Month | Dow | DayAnd)
+		years  = NewParser(Minute | Hour | Dom | Month | Dow | Year)
+	)
+	tests := []struct {
+		parser   Parser
+		spec     string
+		expected []WarningKind
+	}{
+		{standardParser, "CRON_TZ=UTC 0 3 * * *", nil},
+		{standardParser, "CRON_TZ=UTC */5 * * * *", nil},
+		{standardParser, "CRON_TZ=UTC * * * * *", nil},
+		{secondParser, "CRON_TZ=UTC * * * * * *", nil},
+		{standardParser, "@every 1h", nil},
+		{standardParser, "@reboot", nil},
+
+		// Never-firing schedules.
+		{standardParser, "CRON_TZ=UTC 0 0 31 2 *", []WarningKind{WarnNeverFires}},
+		{standardParser, "CRON_TZ=UTC 0 0 30 2 *", []WarningKind{WarnNeverFires}},
+		{years, "CRON_TZ=UTC 0 0 1 1 * 2001", []WarningKind{WarnNeverFires}},
+		{and, "CRON_TZ=UTC 0 0 31 2 *", []WarningKind{WarnNeverFires}},
+
+		// Unintended every-minute and every-second runs.
+		{standardParser, "CRON_TZ=UTC * 3 * * *", []WarningKind{WarnEveryMinute}},
+		{standardParser, "CRON_TZ=UTC * * * * mon", []WarningKind{WarnEveryMinute}},
+		{standardParser, "CRON_TZ=UTC * * 1 * *", []WarningKind{WarnEveryMinute}},
+		{secondParser, "CRON_TZ=UTC * 0 3 * * *", []WarningKind{WarnEverySecond}},
+		{secondParser, "CRON_TZ=UTC * * 3 * * *", []WarningKind{WarnEverySecond}},
+
+		// Day of month and day of week.
+		{standardParser, "CRON_TZ=UTC 0 0 13 * fri", []WarningKind{WarnDomDowOr}},
+		{quartz, "CRON_TZ=UTC 0 0 L * fri", []WarningKind{WarnDomDowOr}},
+		{and, "CRON_TZ=UTC 0 0 13 * fri", nil},
+		{standardParser, "CRON_TZ=UTC 0 0 13 * ?", nil},
+
+		// Redundant ranges.
+		{standardParser, "CRON_TZ=UTC 0-59/15 1-10,5-7 * * *", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 5,5 * * * *", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 0 0 * * 0-6", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 0 0 * jan-dec *", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 0 */1 * * *", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 0 22-2,1 * * *", []WarningKind{WarnRedundantRange}},
+		{standardParser, "CRON_TZ=UTC 0 1-3,2-5 * * *", nil},
+		{quartz, "CRON_TZ=UTC 0 0 L,1 * *", nil},
+	}
+	for _, test := range tests {
+		warnings, err := test.parser.Lint(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		var actual []WarningKind
+		for _, w := range warnings {
+			actual = append(actual, w.Kind)
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s => expected %v, got %v", test.spec, test.expected, warnings)
+		}
+	}
+
+	if _, err := standardParser.Lint("0 25 * * *"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestLintMessages(t *testing.T) {
+	warnings, _ := standardParser.Lint("CRON_TZ=UTC 0 1-10,5-7 * * *")
+	if len(warnings) != 1 || warnings[0].Field != "hour" || warnings[0].String() != "hour field: 5-7 is already covered by the rest of the field" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	warnings, _ = standardParser.Lint("CRON_TZ=UTC 0 0 * * 0-6")
+	if len(warnings) != 1 || warnings[0].String() != "day of week field: 0-6 matches every day of week" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
+
+func TestLintDST(t *testing.T) {
+	now := getTime("2012-01-01T00:00:00-0000")
+	tests := []struct {
+		spec     string
+		policy   DSTPolicy
+		expected []string
+	}{
+		{"CRON_TZ=America/New_York 30 2 * * *", 0, []string{
+			"02:30:00 on 2012-03-11 does not exist in America/New_York",
+		}},
+		{"CRON_TZ=America/New_York 30 1 * * *", 0, []string{
+			"01:30:00 on 2012-11-04 occurs twice in America/New_York",
+		}},
+		{"CRON_TZ=America/New_York */30 1-2 * * *", 0, []string{
+			"02:00:00 on 2012-03-11 does not exist",
+			"01:00:00 on 2012-11-04 occurs twice",
+		}},
+		{"CRON_TZ=America/New_York */30 1-2 * * *", DSTNextValid, []string{
+			"01:00:00 on 2012-11-04 occurs twice",
+		}},
+		{"CRON_TZ=America/New_York */30 1-2 * * *", DSTNextValid | DSTOnce, nil},
+		{"CRON_TZ=Europe/London 30 1 * * *", 0, []string{
+			"01:30:00 on 2012-03-25 does not exist in Europe/London",
+			"01:30:00 on 2012-10-28 occurs twice in Europe/London",
+		}},
+		{"CRON_TZ=America/Sao_Paulo 0 0 * * *", 0, []string{
+			"00:00:00 on 2012-10-21 does not exist in America/Sao_Paulo",
+		}},
+		{"CRON_TZ=America/New_York 30 2 * 7 *", 0, nil},
+		{"CRON_TZ=America/New_York 0 3 * * *", 0, nil},
+		{"CRON_TZ=America/New_York 0 * * * *", 0, nil},
+		{"CRON_TZ=Asia/Tokyo 30 2 * * *", 0, nil},
+		{"CRON_TZ=UTC 30 2 * * *", 0, nil},
+	}
+	for _, test := range tests {
+		sched, err := standardParser.WithDSTPolicy(test.policy).Parse(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		warnings := sched.(*SpecSchedule).lint(now)
+		if len(warnings) != len(test.expected) {
+			t.Errorf("%s => expected %d warnings, got %v", test.spec, len(test.expected), warnings)
+			continue
+		}
+		for i, w := range warnings {
+			if w.Kind != WarnDST || !strings.HasPrefix(w.Msg, test.expected[i]) {
+				t.Errorf("%s => expected %q, got %v", test.spec, test.expected[i], w)
+			}
+		}
+	}
+}
+
+func TestLintLocal(t *testing.T) {
+	// Schedules without a time zone are checked in the zone of the given time.
+	sched, _ := standardParser.Parse("30 2 * * *")
+	loc, _ := time.LoadLocation("America/New_York")
+	warnings := sched.(*SpecSchedule).lint(time.Date(2012, time.January, 1, 0, 0, 0, 0, loc))
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Msg, "America/New_York") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+}
@@ -0,0 +1,109 @@
+package cron
+
+import "fmt"
+
+// ParseErrorReason是解析错误的原因，可供程序判断。
+type ParseErrorReason int
+
+const (
+	ReasonSyntax            ParseErrorReason = iota + 1 // 无法识别的表达式，例如"5--5"或非数字
+	ReasonOutOfRange                                    // 值超出了字段允许的范围
+	ReasonBadStep                                       // 步长不是正整数
+	ReasonFieldCount                                    // 字段的数量不对（包括空的spec）
+	ReasonUnknownDescriptor                             // 无法识别的描述符，例如"@fortnightly"
+	ReasonBadLocation                                   // 无法加载CRON_TZ=或TZ=指定的时区
+	ReasonUnsupported                                   // 解析器没有启用该功能，例如描述符或H表达式
+	ReasonDialect                                       // 目标方言不接受的写法，例如Kubernetes中的"@every"
+	ReasonDescriptorCycle                               // 用户定义的描述符直接或间接地引用了自身
+)
+
+var reasonNames = map[ParseErrorReason]string{
+	ReasonSyntax:            "syntax",
+	ReasonOutOfRange:        "out of range",
+	ReasonBadStep:           "bad step",
+	ReasonFieldCount:        "field count",
+	ReasonUnknownDescriptor: "unknown descriptor",
+	ReasonBadLocation:       "bad location",
+	ReasonUnsupported:       "unsupported",
+	ReasonDialect:           "dialect",
+	ReasonDescriptorCycle:   "descriptor cycle",
+}
+
+func (r ParseErrorReason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("ParseErrorReason(%d)", int(r))
+}
+
+// ParseError描述了解析spec时出现的错误，以及出错的位置。
+// Parser返回的所有错误都是*ParseError，可以通过errors.As获取：
+//
+//  var perr *cron.ParseError
+//  if errors.As(err, &perr) {
+//  	highlight(perr.Offset, perr.Length)
+//  }
+//
+type ParseError struct {
+	// Spec是传给解析器的完整spec。
+	Spec string
+
+	// Field是出错字段的名称，例如"minute"或"day of week"；
+	// 错误与具体字段无关（例如字段数量错误）时为空。
+	Field string
+
+	// Offset和Length是出错的部分在Spec中的字节偏移和长度。
+	Offset, Length int
+
+	// Token是出错的表达式，例如"5--5"。
+	Token string
+
+	// Reason是出错的原因。
+	Reason ParseErrorReason
+
+	// Msg是对错误的描述。
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	if e.Field != "" {
+		return e.Field + " field: " + e.Msg
+	}
+	return e.Msg
+}
+
+// fieldNames是places中每个字段的名称，用于ParseError.Field。
+var fieldNames = []string{
+	"second",
+	"minute",
+	"hour",
+	"day of month",
+	"month",
+	"day of week",
+	"year",
+	"millisecond",
+}
+
+// parseErrorf返回给定原因的ParseError，其Token和位置由调用方通过atToken补充。
+func parseErrorf(reason ParseErrorReason, format string, args ...interface{}) *ParseError {
+	return &ParseError{Reason: reason, Msg: fmt.Sprintf(format, args...)}
+}
+
+// atToken将err（如果是*ParseError）定位到从offset开始的token上，并返回err。
+// 已经定位过的错误不会被覆盖，因此最内层的调用方给出的位置优先。
+func atToken(err error, token string, offset int) error {
+	if perr, ok := err.(*ParseError); ok && perr.Token == "" {
+		perr.Token = token
+		perr.Offset = offset
+		perr.Length = len(token)
+	}
+	return err
+}
+
+// shiftError将err（如果是*ParseError）的偏移向后移动offset个字节，并返回err。
+func shiftError(err error, offset int) error {
+	if perr, ok := err.(*ParseError); ok {
+		perr.Offset += offset
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	quartzParser := NewParser(Minute | Hour | Dom | Month | Dow | QuartzModifiers | YearOptional)
+	tests := []struct {
+		parser Parser
+		spec   string
+		field  string
+		offset int
+		token  string
+		reason ParseErrorReason
+	}{
+		{standardParser, "5-30 0 * * *", "", 0, "", 0},
+		{standardParser, "5-70 * * * *", "minute", 0, "5-70", ReasonOutOfRange},
+		{standardParser, "0 1,2,3x * * *", "hour", 6, "3x", ReasonSyntax},
+		{standardParser, "0  0 * 13 *", "month", 7, "13", ReasonOutOfRange},
+		{standardParser, "0 */0 * * *", "hour", 2, "*/0", ReasonBadStep},
+		{standardParser, "0 0 * * mon-fri,sun--sat", "day of week", 16, "sun--sat", ReasonSyntax},
+		{standardParser, "CRON_TZ=UTC 61 * * * *", "minute", 12, "61", ReasonOutOfRange},
+		{standardParser, "TZ=Nowhere/Else 0 * * * *", "", 3, "Nowhere/Else", ReasonBadLocation},
+		{standardParser, "0 * * *", "", 0, "0 * * *", ReasonFieldCount},
+		{standardParser, "0 * * * * *", "", 0, "0 * * * * *", ReasonFieldCount},
+		{standardParser, "", "", 0, "", ReasonFieldCount},
+		{standardParser, "TZ=UTC @fortnightly", "", 7, "@fortnightly", ReasonUnknownDescriptor},
+		{standardParser, "@every 5x", "", 7, "5x", ReasonSyntax},
+		{NewParser(Minute | Hour | Dom | Month | Dow), "@daily", "", 0, "@daily", ReasonUnsupported},
+		{standardParser, "H * * * *", "minute", 0, "H", ReasonUnsupported},
+		{secondParser, "0 0 0 * * 8", "day of week", 10, "8", ReasonOutOfRange},
+		{secondParser, "0 0 0 1,32 *", "day of month", 8, "32", ReasonOutOfRange},
+		{quartzParser, "0 0 ? * fri#6", "day of week", 8, "fri#6", ReasonOutOfRange},
+		{quartzParser, "0 0 L-31 * ?", "day of month", 4, "L-31", ReasonOutOfRange},
+		{quartzParser, "0 0 1 1 * 2030-2027", "year", 10, "2030-2027", ReasonOutOfRange},
+	}
+
+	for _, test := range tests {
+		_, err := test.parser.Parse(test.spec)
+		if test.reason == 0 {
+			if err != nil {
+				t.Errorf("%q => unexpected error %v", test.spec, err)
+			}
+			continue
+		}
+
+		var perr *ParseError
+		if !errors.As(fmt.Errorf("wrapped: %w", err), &perr) {
+			t.Errorf("%q => expected a *ParseError, got %v", test.spec, err)
+			continue
+		}
+		if perr.Spec != test.spec || perr.Field != test.field || perr.Offset != test.offset ||
+			perr.Token != test.token || perr.Length != len(test.token) || perr.Reason != test.reason {
+			t.Errorf("%q => expected %s error in %q at %d (%q), got %s error in %q at %d+%d (%q)",
+				test.spec, test.reason, test.field, test.offset, test.token,
+				perr.Reason, perr.Field, perr.Offset, perr.Length, perr.Token)
+		}
+		if test.token != "" && test.spec[perr.Offset:perr.Offset+perr.Length] != test.token {
+			t.Errorf("%q => offset %d does not point at %q", test.spec, perr.Offset, test.token)
+		}
+	}
+}
+
+func TestParseErrorString(t *testing.T) {
+	_, err := standardParser.Parse("0 25 * * *")
+	const expected = "hour field: end of range (25) above maximum (23): 25"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+	if ReasonBadStep.String() != "bad step" {
+		t.Errorf("unexpected reason name %q", ReasonBadStep.String())
+	}
+}
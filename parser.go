@@ -1,11 +1,12 @@
 package cron
 
 import (
-	"fmt"
+	"hash/fnv"
 	"math"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // 用于创建解析器的配置选项。大多数选项指定应包括哪些字段，而其他选项则启用功能。
@@ -14,15 +15,24 @@ type ParseOption int
 
 // 1, 2, 4, 8...
 const (
-	Second         ParseOption = 1 << iota // 秒字段，默认值为0
-	SecondOptional                         // 可选的秒字段，默认值为0
-	Minute                                 // 分钟字段，默认值为0
-	Hour                                   // 小时字段，默认值为0
-	Dom                                    // 月份中的第几天字段，默认值为*
-	Month                                  // 月份字段，默认值为*
-	Dow                                    // 周中的第几天字段，默认值为*
-	DowOptional                            // 可选周中的第几天字段，默认值为*
-	Descriptor                             // 允许使用诸如@monthly，@weekly等的描述符。
+	Second              ParseOption = 1 << iota // 秒字段，默认值为0
+	SecondOptional                              // 可选的秒字段，默认值为0
+	Minute                                      // 分钟字段，默认值为0
+	Hour                                        // 小时字段，默认值为0
+	Dom                                         // 月份中的第几天字段，默认值为*
+	Month                                       // 月份字段，默认值为*
+	Dow                                         // 周中的第几天字段，默认值为*
+	DowOptional                                 // 可选周中的第几天字段，默认值为*
+	Descriptor                                  // 允许使用诸如@monthly，@weekly等的描述符。
+	QuartzModifiers                             // 允许在Dom和Dow字段中使用Quartz风格的L、W和#修饰符
+	Year                                        // 年份字段，默认值为*
+	YearOptional                                // 可选的年份字段，默认值为*
+	Millisecond                                 // 毫秒字段，位于秒字段之前，默认值为0
+	MillisecondOptional                         // 可选的毫秒字段，默认值为0
+	DayAnd                                      // 日期和星期字段必须同时匹配，而不是Vixie cron的规则
+	DayQuartz                                   // 与Quartz相同，日期和星期字段中必须恰好有一个为?
+	RRule                                       // 允许使用以"RRULE:"或"DTSTART"开头的RFC 5545重复规则
+	RepeatingInterval                           // 允许使用"R5/2026-01-01T00:00:00Z/PT1H"这样的ISO 8601重复时间间隔
 )
 
 var places = []ParseOption{
@@ -32,8 +42,14 @@ var places = []ParseOption{
 	Dom,
 	Month,
 	Dow,
+	Year,
+	Millisecond,
 }
 
+// fieldOrder是places中各个位置在spec中出现的顺序：毫秒字段在最前面。
+// 毫秒字段放在places的最后，以免改变其他位置的下标（散列值由下标派生）。
+var fieldOrder = []int{7, 0, 1, 2, 3, 4, 5, 6}
+
 var defaults = []string{
 	"0",
 	"0",
@@ -41,11 +57,16 @@ var defaults = []string{
 	"*",
 	"*",
 	"*",
+	"*",
+	"0",
 }
 
 // 可以配置的自定义解析器。
 type Parser struct {
-	options ParseOption
+	options     ParseOption
+	calendar    *Calendar
+	dst         DSTPolicy
+	descriptors *Descriptors
 }
 
 // NewParser用自定义选项创建一个解析器。
@@ -75,86 +96,278 @@ func NewParser(options ParseOption) Parser {
 	if options&SecondOptional > 0 {
 		optionals++
 	}
+	if options&YearOptional > 0 {
+		optionals++
+	}
+	if options&MillisecondOptional > 0 {
+		optionals++
+	}
 	if optionals > 1 {
 		panic("multiple optionals may not be configured")
 	}
-	return Parser{options}
+	if options&DayAnd > 0 && options&DayQuartz > 0 {
+		panic("DayAnd and DayQuartz may not both be configured")
+	}
+	return Parser{options: options}
+}
+
+// WithCalendar返回使用cal计算"@businessday"描述符的解析器副本。
+// 未设置日历时，只有周六和周日被视为非工作日。
+func (p Parser) WithCalendar(cal *Calendar) Parser {
+	p.calendar = cal
+	return p
+}
+
+// WithDescriptors返回同时接受d中用户定义的描述符的解析器副本。
+// 解析器必须启用了Descriptor选项。
+//
+// 示例
+//
+//  d := cron.NewDescriptors()
+//  d.Define("@business-hours", "0 9-17 * * mon-fri")
+//  d.Define("@nightly-batch", "CRON_TZ=UTC 30 2 * * *")
+//  p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor).WithDescriptors(d)
+//  sched, err := p.Parse("@business-hours")
+//
+func (p Parser) WithDescriptors(d *Descriptors) Parser {
+	p.descriptors = d
+	return p
+}
+
+// WithDSTPolicy返回一个解析器副本，它解析出的SpecSchedule按照policy处理夏令时转换。
+// 如果policy同时包括DSTSkip和DSTNextValid，或同时包括DSTOnce和DSTTwice，它会恐慌。
+//
+// 示例
+//
+//  // 在跳过的时间之后立即运行，在重复的时间只运行一次
+//  p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).
+//  	WithDSTPolicy(cron.DSTNextValid | cron.DSTOnce)
+//  sched, err := p.Parse("CRON_TZ=America/New_York 30 2 * * *")
+//
+func (p Parser) WithDSTPolicy(policy DSTPolicy) Parser {
+	if policy&DSTSkip > 0 && policy&DSTNextValid > 0 {
+		panic("DSTSkip and DSTNextValid may not both be configured")
+	}
+	if policy&DSTOnce > 0 && policy&DSTTwice > 0 {
+		panic("DSTOnce and DSTTwice may not both be configured")
+	}
+	p.dst = policy
+	return p
 }
 
 // Parse返回代表给定spec的新crontab时间表。
 // 如果spec不是有效的，将返回一个描述性的错误
 // 它接受由NewParser配置的crontab specs和功能。
 func (p Parser) Parse(spec string) (Schedule, error) {
+	return p.parse(spec, "", false)
+}
+
+// ParseWithKey与Parse相同，但额外接受Jenkins风格的散列表达式"H"、"H(a-b)"和"H/step"。
+// 每个字段中H的取值由key（例如作业名称）确定：同一个key总是得到相同的时间表，
+// 而不同的key会被分散到不同的时间上，以避免大量作业在同一时刻运行。
+//
+// 示例
+//
+//  // 每天运行一次，分钟和小时（0-7之间）由"nightly-report"确定
+//  p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+//  sched, err := p.ParseWithKey("H H(0-7) * * *", "nightly-report")
+//
+func (p Parser) ParseWithKey(spec, key string) (Schedule, error) {
+	return p.parse(spec, key, true)
+}
+
+// parse实现了Parse和ParseWithKey。只有hashed为true时才接受H表达式。
+// 返回的错误都是*ParseError，其位置相对于完整的spec。
+func (p Parser) parse(spec, key string, hashed bool) (Schedule, error) {
+	schedule, err := p.parseSpec(spec, key, hashed, nil)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = spec
+	}
+	if s, ok := schedule.(*SpecSchedule); ok {
+		s.DST = p.dst
+	}
+	return schedule, err
+}
+
+// parseSpec解析spec。expanding是正在展开的用户定义描述符，用于检测循环。
+func (p Parser) parseSpec(spec, key string, hashed bool, expanding []string) (Schedule, error) {
 	if len(spec) == 0 {
-		return nil, fmt.Errorf("empty spec string")
+		return nil, parseErrorf(ReasonFieldCount, "empty spec string")
 	}
 
-	// 提取时区（如果存在）
-	var loc = time.Local
+	// 提取时区（如果存在）。base是剩余部分在原始spec中的偏移。
+	var (
+		loc  = time.Local
+		base = 0
+	)
 	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
 		var err error
 		i := strings.Index(spec, " ")
+		if i < 0 {
+			i = len(spec)
+		}
 		eq := strings.Index(spec, "=")
 		if loc, err = time.LoadLocation(spec[eq+1 : i]); err != nil {
-			return nil, fmt.Errorf("provided bad location %s: %v", spec[eq+1:i], err)
+			return nil, atToken(parseErrorf(ReasonBadLocation, "provided bad location %s: %v", spec[eq+1:i], err), spec[eq+1:i], eq+1)
+		}
+		rest := strings.TrimLeftFunc(spec[i:], unicode.IsSpace)
+		base = len(spec) - len(rest)
+		spec = strings.TrimRightFunc(rest, unicode.IsSpace)
+	}
+
+	// 处理RFC 5545的重复规则，如果配置了的话
+	if isRRule(spec) {
+		if p.options&RRule == 0 {
+			return nil, atToken(parseErrorf(ReasonUnsupported, "parser does not accept RRULE specs: %v", spec), spec, base)
 		}
-		spec = strings.TrimSpace(spec[i:])
+		schedule, err := parseRRule(spec, loc)
+		if err != nil {
+			return nil, shiftError(err, base)
+		}
+		return schedule, nil
+	}
+
+	// 处理ISO 8601的重复时间间隔，如果配置了的话
+	if isRepeatingInterval(spec) {
+		if p.options&RepeatingInterval == 0 {
+			return nil, atToken(parseErrorf(ReasonUnsupported, "parser does not accept repeating intervals: %v", spec), spec, base)
+		}
+		schedule, err := parseInterval(spec, loc)
+		if err != nil {
+			return nil, shiftError(err, base)
+		}
+		return schedule, nil
 	}
 
 	// 处理命名的时间表（描述符），如果配置了的话
 	if strings.HasPrefix(spec, "@") {
 		if p.options&Descriptor == 0 {
-			return nil, fmt.Errorf("parser does not accept descriptors: %v", spec)
+			return nil, atToken(parseErrorf(ReasonUnsupported, "parser does not accept descriptors: %v", spec), spec, base)
 		}
-		return parseDescriptor(spec, loc)
+		schedule, err := p.parseDescriptor(spec, loc, key, hashed, expanding)
+		return schedule, shiftError(err, base)
 	}
 
-	// 在空白处分割。
-	fields := strings.Fields(spec)
+	// 在空白处分割，并记录每个字段在spec中的偏移。
+	fields, offsets := splitFields(spec)
 
-	// 验证并填写任何省略或可选的字段
-	var err error
-	fields, err = normalizeFields(fields, p.options)
+	// 验证并找出每个位置对应的字段，省略的字段使用默认值
+	indexes, err := fieldIndexes(len(fields), p.options)
 	if err != nil {
-		return nil, err
+		return nil, atToken(err, spec, base)
 	}
+	expanded := expandFields(fields, indexes)
+
+	schedule := &SpecSchedule{Location: loc, DomAndDow: p.options&DayAnd > 0}
 
-	field := func(field string, r bounds) uint64 {
+	// 将place处字段的错误定位到该字段在原始spec中的位置上
+	locate := func(err error, place int) error {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Field = fieldNames[place]
+			if i := indexes[place]; i >= 0 {
+				perr.Offset += base + offsets[i]
+			}
+		}
+		return err
+	}
+
+	// 为每个字段派生出各自的散列值，使同一个key在不同字段上的取值互不相关
+	fieldHash := func(place int) *uint64 {
+		if !hashed {
+			return nil
+		}
+		h := hashKey(key, place)
+		return &h
+	}
+
+	field := func(field string, r bounds, place int) uint64 {
 		if err != nil {
 			return 0
 		}
 		var bits uint64
-		bits, err = getField(field, r)
+		bits, err = getField(field, r, fieldHash(place))
+		err = locate(err, place)
 		return bits
 	}
 
-	var (
-		second     = field(fields[0], seconds)
-		minute     = field(fields[1], minutes)
-		hour       = field(fields[2], hours)
-		dayofmonth = field(fields[3], dom)
-		month      = field(fields[4], months)
-		dayofweek  = field(fields[5], dow)
-	)
+	// 启用修饰符后，Dom和Dow字段中的修饰符直接记录在schedule上
+	dayField := func(spec string, r bounds, place int, modifier dayModifier) uint64 {
+		if p.options&QuartzModifiers == 0 {
+			return field(spec, r, place)
+		}
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = getDayField(spec, r, fieldHash(place), schedule, modifier)
+		err = locate(err, place)
+		return bits
+	}
+
+	// Quartz要求在日期和星期中恰好有一个使用?，以明确哪一个字段生效。
+	if p.options&DayQuartz > 0 && indexes[3] >= 0 && indexes[5] >= 0 {
+		domAny, dowAny := expanded[3] == "?", expanded[5] == "?"
+		if domAny == dowAny {
+			err = parseErrorf(ReasonSyntax, "exactly one of day-of-month and day-of-week must be ?: %s", expanded[5])
+			return nil, locate(atToken(err, expanded[5], 0), 5)
+		}
+	}
+
+	schedule.Second = field(expanded[0], seconds, 0)
+	schedule.Minute = field(expanded[1], minutes, 1)
+	schedule.Hour = field(expanded[2], hours, 2)
+	schedule.Dom = dayField(expanded[3], dom, 3, parseDomModifier)
+	schedule.Month = field(expanded[4], months, 4)
+	schedule.Dow = dayField(expanded[5], dow, 5, parseDowModifier)
 	if err != nil {
 		return nil, err
 	}
+	if schedule.Year, err = getYearField(expanded[6]); err != nil {
+		return nil, locate(err, 6)
+	}
+	if schedule.Millisecond, err = getMillisecondField(expanded[7]); err != nil {
+		return nil, locate(err, 7)
+	}
 
-	return &SpecSchedule{
-		Second:   second,
-		Minute:   minute,
-		Hour:     hour,
-		Dom:      dayofmonth,
-		Month:    month,
-		Dow:      dayofweek,
-		Location: loc,
-	}, nil
+	return schedule, nil
+}
+
+// splitFields与strings.Fields相同，但同时返回每个字段在s中的字节偏移。
+func splitFields(s string) (fields []string, offsets []int) {
+	start := -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields = append(fields, s[start:i])
+				offsets = append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, s[start:])
+		offsets = append(offsets, start)
+	}
+	return fields, offsets
 }
 
-// normalizeFields接收时间字段的子集，并返回完整集
-// 其中包含未设置字段的默认值（零）。
-// 作为执行此功能的一部分，它还将验证所提供的字段是否与配置的选项兼容。
-func normalizeFields(fields []string, options ParseOption) ([]string, error) {
+// expandFields根据fieldIndexes的结果返回完整的字段集，未提供的字段使用默认值。
+func expandFields(fields []string, indexes []int) []string {
+	expandedFields := make([]string, len(places))
+	copy(expandedFields, defaults)
+	for place, i := range indexes {
+		if i >= 0 {
+			expandedFields[place] = fields[i]
+		}
+	}
+	return expandedFields
+}
+
+// fieldIndexes验证count个字段是否与配置的选项兼容，
+// 并返回places中每个位置对应的字段下标；未提供的字段为-1，使用默认值。
+func fieldIndexes(count int, options ParseOption) ([]int, error) {
 	// 验证可选选项并将其字段添加到选项
 	optionals := 0
 	if options&SecondOptional > 0 {
@@ -165,8 +378,16 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 		options |= Dow
 		optionals++
 	}
+	if options&YearOptional > 0 {
+		options |= Year
+		optionals++
+	}
+	if options&MillisecondOptional > 0 {
+		options |= Millisecond
+		optionals++
+	}
 	if optionals > 1 {
-		return nil, fmt.Errorf("multiple optionals may not be configured")
+		return nil, parseErrorf(ReasonUnsupported, "multiple optionals may not be configured")
 	}
 
 	// 找出我们需要多少个字段
@@ -179,36 +400,40 @@ func normalizeFields(fields []string, options ParseOption) ([]string, error) {
 	min := max - optionals
 
 	// 验证字段的数量
-	if count := len(fields); count < min || count > max {
+	if count < min || count > max {
 		if min == max {
-			return nil, fmt.Errorf("expected exactly %d fields, found %d: %s", min, count, fields)
+			return nil, parseErrorf(ReasonFieldCount, "expected exactly %d fields, found %d", min, count)
 		}
-		return nil, fmt.Errorf("expected %d to %d fields, found %d: %s", min, max, count, fields)
+		return nil, parseErrorf(ReasonFieldCount, "expected %d to %d fields, found %d", min, max, count)
 	}
 
-	// 如果未提供，则填充可选字段
-	if min < max && len(fields) == min {
+	// 如果未提供，则省略可选字段
+	if min < max && count == min {
 		switch {
 		case options&DowOptional > 0:
-			fields = append(fields, defaults[5]) // TODO: improve access to default
+			options &^= Dow
+		case options&YearOptional > 0:
+			options &^= Year
 		case options&SecondOptional > 0:
-			fields = append([]string{defaults[0]}, fields...)
+			options &^= Second
+		case options&MillisecondOptional > 0:
+			options &^= Millisecond
 		default:
-			return nil, fmt.Errorf("unknown optional field")
+			return nil, parseErrorf(ReasonUnsupported, "unknown optional field")
 		}
 	}
 
-	// 使用默认值填充所有不属于可选的字段。
+	// 按顺序为每个包含的位置分配字段，其余位置使用默认值。
 	n := 0
-	expandedFields := make([]string, len(places))
-	copy(expandedFields, defaults)
-	for i, place := range places {
-		if options&place > 0 {
-			expandedFields[i] = fields[n]
+	indexes := make([]int, len(places))
+	for _, i := range fieldOrder {
+		indexes[i] = -1
+		if options&places[i] > 0 {
+			indexes[i] = n
 			n++
 		}
 	}
-	return expandedFields, nil
+	return indexes, nil
 }
 
 var standardParser = NewParser(
@@ -228,32 +453,210 @@ func ParseStandard(standardSpec string) (Schedule, error) {
 
 // getField返回一个Int，其位设置为代表该字段表示的所有时间或错误解析字段的值。
 //  “field”是用逗​​号分隔的“ranges”列表。
-func getField(field string, r bounds) (uint64, error) {
+// hash是该字段中"H"表达式使用的散列值，为nil时不允许使用"H"。
+func getField(field string, r bounds, hash *uint64) (uint64, error) {
 	var bits uint64
-	ranges := strings.FieldsFunc(field, func(r rune) bool { return r == ',' })
-	for _, expr := range ranges {
-		bit, err := getRange(expr, r)
-		if err != nil {
-			return bits, err
+	err := forEachRange(field, func(expr string) error {
+		bit, err := getRange(expr, r, hash)
+		bits |= bit
+		return err
+	})
+	return bits, err
+}
+
+// dayModifier识别expr中Quartz风格的修饰符并记录到s上，返回修饰符表示的普通取值的位。
+// 如果expr不是修饰符，则返回false。
+type dayModifier func(expr string, s *SpecSchedule) (bits uint64, ok bool, err error)
+
+// getDayField与getField相同，但每个范围都会先交给modifier处理。
+func getDayField(field string, r bounds, hash *uint64, s *SpecSchedule, modifier dayModifier) (uint64, error) {
+	var bits uint64
+	err := forEachRange(field, func(expr string) error {
+		bit, ok, err := modifier(expr, s)
+		if !ok && err == nil {
+			bit, err = getRange(expr, r, hash)
 		}
 		bits |= bit
+		return err
+	})
+	return bits, err
+}
+
+// forEachRange对field中用逗号分隔的每个非空范围调用fn，
+// 并将fn返回的错误定位到该范围在field中的位置上。
+func forEachRange(field string, fn func(expr string) error) error {
+	offset := 0
+	for _, expr := range strings.Split(field, ",") {
+		if expr != "" {
+			if err := fn(expr); err != nil {
+				return atToken(err, expr, offset)
+			}
+		}
+		offset += len(expr) + 1
+	}
+	return nil
+}
+
+// parseDomModifier解析Dom字段中的修饰符：
+//   "L" | "L-" number | "LW" | number "W"
+// 如果expr不是修饰符，则返回false。
+func parseDomModifier(expr string, s *SpecSchedule) (uint64, bool, error) {
+	upper := strings.ToUpper(expr)
+	switch {
+	case upper == "L":
+		s.DomLast |= 1
+	case upper == "LW":
+		s.DomWeekday |= 1
+	case strings.HasPrefix(upper, "L-"):
+		offset, err := mustParseInt(expr[2:])
+		if err != nil {
+			return 0, true, err
+		}
+		if offset > dom.max-dom.min {
+			return 0, true, parseErrorf(ReasonOutOfRange, "offset from last day (%d) above maximum (%d): %s", offset, dom.max-dom.min, expr)
+		}
+		s.DomLast |= 1 << offset
+	case strings.HasSuffix(upper, "W"):
+		day, err := mustParseInt(expr[:len(expr)-1])
+		if err != nil {
+			return 0, true, err
+		}
+		if day < dom.min || day > dom.max {
+			return 0, true, parseErrorf(ReasonOutOfRange, "day of nearest weekday (%d) out of range (%d-%d): %s", day, dom.min, dom.max, expr)
+		}
+		s.DomWeekday |= 1 << day
+	default:
+		return 0, false, nil
+	}
+	return 0, true, nil
+}
+
+// parseDowModifier解析Dow字段中的修饰符：
+//   "L" | number-or-name "L" | number-or-name "#" number
+// 单独的"L"与Quartz相同，表示一周的最后一天，即周六。
+// 如果expr不是修饰符，则返回false。
+func parseDowModifier(expr string, s *SpecSchedule) (uint64, bool, error) {
+	if strings.ToUpper(expr) == "L" {
+		return 1 << dow.max, true, nil
+	}
+	if i := strings.Index(expr, "#"); i >= 0 {
+		day, err := parseIntOrName(expr[:i], dow.names)
+		if err != nil {
+			return 0, true, err
+		}
+		nth, err := mustParseInt(expr[i+1:])
+		if err != nil {
+			return 0, true, err
+		}
+		if day > dow.max {
+			return 0, true, parseErrorf(ReasonOutOfRange, "day of week (%d) above maximum (%d): %s", day, dow.max, expr)
+		}
+		if nth < 1 || nth > 5 {
+			return 0, true, parseErrorf(ReasonOutOfRange, "occurrence (%d) must be between 1 and 5: %s", nth, expr)
+		}
+		s.DowNth |= 1 << ((nth-1)*7 + day)
+		return 0, true, nil
+	}
+	if len(expr) > 1 && strings.HasSuffix(strings.ToUpper(expr), "L") {
+		day, err := parseIntOrName(expr[:len(expr)-1], dow.names)
+		if err != nil {
+			return 0, true, err
+		}
+		if day > dow.max {
+			return 0, true, parseErrorf(ReasonOutOfRange, "day of week (%d) above maximum (%d): %s", day, dow.max, expr)
+		}
+		s.DowLast |= 1 << day
+		return 0, true, nil
+	}
+	return 0, false, nil
+}
+
+// getYearField返回年份字段表示的所有年份（升序）。
+// 如果字段匹配任意年份（例如"*"），则返回nil。
+func getYearField(field string) ([]int, error) {
+	values, star, err := getValues(field, years)
+	if err != nil || star {
+		return nil, err
+	}
+	return values, nil
+}
+
+// getMillisecondField返回毫秒字段表示的所有毫秒数（升序）。
+// 如果字段只匹配0（即整秒），则返回nil。
+func getMillisecondField(field string) ([]int, error) {
+	values, _, err := getValues(field, milliseconds)
+	if err != nil || len(values) == 1 && values[0] == 0 {
+		return nil, err
+	}
+	return values, nil
+}
+
+// getValues返回字段在r范围内表示的所有值（升序），用于无法用位集表示的字段。
+// star报告字段中是否包含不带步长的星号。
+func getValues(field string, r bounds) (values []int, star bool, err error) {
+	matched := make([]bool, r.max-r.min+1)
+	err = forEachRange(field, func(expr string) error {
+		start, end, step, extra, err := parseRange(expr, r, nil)
+		if err != nil {
+			return err
+		}
+		if start > end {
+			return parseErrorf(ReasonOutOfRange, "beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+		}
+		if extra&starBit > 0 {
+			star = true
+		}
+		for v := start; v <= end; v += step {
+			matched[v-r.min] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
 	}
-	return bits, nil
+	for i, ok := range matched {
+		if ok {
+			values = append(values, int(r.min)+i)
+		}
+	}
+	return values, star, nil
 }
 
 // getRange返回给定表达式指示的位：
 //   number | number "-" number [ "/" number ]
-// 或解析范围错误。
-func getRange(expr string, r bounds) (uint64, error) {
+// 或解析范围错误。hash不为nil时还接受parseHash所描述的散列表达式。
+// 起点大于终点的范围（例如小时字段中的"22-2"）会越过最大值回绕到最小值。
+func getRange(expr string, r bounds, hash *uint64) (uint64, error) {
+	start, end, step, extra, err := parseRange(expr, r, hash)
+	if err != nil {
+		return 0, err
+	}
+	if start > end {
+		// 先从起点数到最大值，再从最小值开始按相同的步长数到终点。
+		bits := getBits(start, r.max, step)
+		next := start + ((r.max-start)/step+1)*step - (r.max - r.min + 1)
+		if next <= end {
+			bits |= getBits(next, end, step)
+		}
+		return bits | extra, nil
+	}
+	return getBits(start, end, step) | extra, nil
+}
+
+// parseRange解析并校验getRange所接受的表达式，返回其起点、终点、步长，
+// 以及在表达式为不带步长的星号时返回的starBit。
+// 对于回绕的范围，返回的起点大于终点。
+func parseRange(expr string, r bounds, hash *uint64) (start, end, step uint, extra uint64, err error) {
+	if strings.HasPrefix(expr, "H") {
+		return parseHash(expr, r, hash)
+	}
+
 	var (
-		start, end, step uint
-		rangeAndStep     = strings.Split(expr, "/")
-		lowAndHigh       = strings.Split(rangeAndStep[0], "-")
-		singleDigit      = len(lowAndHigh) == 1
-		err              error
+		rangeAndStep = strings.Split(expr, "/")
+		lowAndHigh   = strings.Split(rangeAndStep[0], "-")
+		singleDigit  = len(lowAndHigh) == 1
 	)
 
-	var extra uint64
 	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
 		start = r.min
 		end = r.max
@@ -261,7 +664,7 @@ func getRange(expr string, r bounds) (uint64, error) {
 	} else {
 		start, err = parseIntOrName(lowAndHigh[0], r.names)
 		if err != nil {
-			return 0, err
+			return 0, 0, 0, 0, err
 		}
 		switch len(lowAndHigh) {
 		case 1:
@@ -269,10 +672,10 @@ func getRange(expr string, r bounds) (uint64, error) {
 		case 2:
 			end, err = parseIntOrName(lowAndHigh[1], r.names)
 			if err != nil {
-				return 0, err
+				return 0, 0, 0, 0, err
 			}
 		default:
-			return 0, fmt.Errorf("too many hyphens: %s", expr)
+			return 0, 0, 0, 0, parseErrorf(ReasonSyntax, "too many hyphens: %s", expr)
 		}
 	}
 
@@ -282,7 +685,7 @@ func getRange(expr string, r bounds) (uint64, error) {
 	case 2:
 		step, err = mustParseInt(rangeAndStep[1])
 		if err != nil {
-			return 0, err
+			return 0, 0, 0, 0, err
 		}
 
 		// Special handling: "N/step" means "N-max/step".
@@ -293,23 +696,110 @@ func getRange(expr string, r bounds) (uint64, error) {
 			extra = 0
 		}
 	default:
-		return 0, fmt.Errorf("too many slashes: %s", expr)
+		return 0, 0, 0, 0, parseErrorf(ReasonSyntax, "too many slashes: %s", expr)
 	}
 
 	if start < r.min {
-		return 0, fmt.Errorf("beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
 	}
 	if end > r.max {
-		return 0, fmt.Errorf("end of range (%d) above maximum (%d): %s", end, r.max, expr)
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "end of range (%d) above maximum (%d): %s", end, r.max, expr)
 	}
-	if start > end {
-		return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+	if end < r.min {
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "end of range (%d) below minimum (%d): %s", end, r.min, expr)
 	}
 	if step == 0 {
-		return 0, fmt.Errorf("step of range should be a positive number: %s", expr)
+		return 0, 0, 0, 0, parseErrorf(ReasonBadStep, "step of range should be a positive number: %s", expr)
 	}
 
-	return getBits(start, end, step) | extra, nil
+	return start, end, step, extra, nil
+}
+
+// parseHash解析Jenkins风格的散列表达式：
+//   "H" [ "(" number "-" number ")" ] [ "/" number ]
+// 其中H的取值由hash确定。"H/step"从[min，min+step)中选出起点，然后以step为步长直到最大值。
+// 与普通的范围相同，low大于high的范围（例如"H(22-2)"）会越过最大值回绕到最小值。
+func parseHash(expr string, r bounds, hash *uint64) (start, end, step uint, extra uint64, err error) {
+	if hash == nil {
+		return 0, 0, 0, 0, parseErrorf(ReasonUnsupported, "hash expressions require a key (see ParseWithKey): %s", expr)
+	}
+
+	var (
+		rangeAndStep = strings.Split(expr, "/")
+		hashRange    = rangeAndStep[0][1:]
+		low, high    = r.min, r.max
+	)
+	if hashRange != "" {
+		if !strings.HasPrefix(hashRange, "(") || !strings.HasSuffix(hashRange, ")") {
+			return 0, 0, 0, 0, parseErrorf(ReasonSyntax, "malformed hash expression: %s", expr)
+		}
+		lowAndHigh := strings.Split(hashRange[1:len(hashRange)-1], "-")
+		if len(lowAndHigh) != 2 {
+			return 0, 0, 0, 0, parseErrorf(ReasonSyntax, "hash range must be of the form H(low-high): %s", expr)
+		}
+		if low, err = parseIntOrName(lowAndHigh[0], r.names); err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if high, err = parseIntOrName(lowAndHigh[1], r.names); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+		step = 1
+	case 2:
+		if step, err = mustParseInt(rangeAndStep[1]); err != nil {
+			return 0, 0, 0, 0, err
+		}
+	default:
+		return 0, 0, 0, 0, parseErrorf(ReasonSyntax, "too many slashes: %s", expr)
+	}
+
+	if low < r.min {
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "beginning of range (%d) below minimum (%d): %s", low, r.min, expr)
+	}
+	if high > r.max {
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "end of range (%d) above maximum (%d): %s", high, r.max, expr)
+	}
+	if high < r.min {
+		return 0, 0, 0, 0, parseErrorf(ReasonOutOfRange, "end of range (%d) below minimum (%d): %s", high, r.min, expr)
+	}
+	if step == 0 {
+		return 0, 0, 0, 0, parseErrorf(ReasonBadStep, "step of range should be a positive number: %s", expr)
+	}
+
+	// size是范围中取值的个数；回绕的范围包括[low，max]和[min，high]。
+	size := high - low + 1
+	if low > high {
+		size = (r.max - low + 1) + (high - r.min + 1)
+	}
+	// offset返回范围中第n个取值（从0开始），越过最大值时回绕到最小值。
+	offset := func(n uint) uint {
+		if v := low + n; v <= r.max {
+			return v
+		}
+		return low + n - (r.max - r.min + 1)
+	}
+
+	if len(rangeAndStep) == 1 {
+		start = offset(uint(*hash % uint64(size)))
+		return start, start, 1, 0, nil
+	}
+	// 起点不能超出范围，因此步长大于范围时按范围的大小取模。
+	span := step
+	if span > size {
+		span = size
+	}
+	return offset(uint(*hash % uint64(span))), high, step, 0, nil
+}
+
+// hashKey返回key在给定字段位置上的散列值。
+func hashKey(key string, place int) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h.Write([]byte{byte(place)})
+	return h.Sum64()
 }
 
 // parseIntOrName返回expr中包含的（可能命名的）整数。
@@ -326,10 +816,10 @@ func parseIntOrName(expr string, names map[string]uint) (uint, error) {
 func mustParseInt(expr string) (uint, error) {
 	num, err := strconv.Atoi(expr)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse int from %s: %s", expr, err)
+		return 0, parseErrorf(ReasonSyntax, "failed to parse int from %s: %s", expr, err)
 	}
 	if num < 0 {
-		return 0, fmt.Errorf("negative number (%d) not allowed: %s", num, expr)
+		return 0, parseErrorf(ReasonOutOfRange, "negative number (%d) not allowed: %s", num, expr)
 	}
 
 	return uint(num), nil
@@ -357,7 +847,11 @@ func all(r bounds) uint64 {
 }
 
 // parseDescriptor返回该表达式的预定义时间表，如果没有匹配项，则返回错误。
-func parseDescriptor(descriptor string, loc *time.Location) (Schedule, error) {
+func (p Parser) parseDescriptor(descriptor string, loc *time.Location, key string, hashed bool, expanding []string) (Schedule, error) {
+	if p.descriptors.defines(descriptor) {
+		return p.parseUserDescriptor(descriptor, loc, key, hashed, expanding)
+	}
+
 	switch descriptor {
 	case "@yearly", "@annually":
 		return &SpecSchedule{
@@ -414,16 +908,95 @@ func parseDescriptor(descriptor string, loc *time.Location) (Schedule, error) {
 			Location: loc,
 		}, nil
 
+	case "@reboot":
+		return RebootSchedule{}, nil
 	}
 
 	const every = "@every "
 	if strings.HasPrefix(descriptor, every) {
-		duration, err := time.ParseDuration(descriptor[len(every):])
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse duration %s: %s", descriptor, err)
+		return parseEvery(descriptor, len(every), loc)
+	}
+
+	const businessDay = "@businessday "
+	if strings.HasPrefix(descriptor, businessDay) {
+		return parseBusinessDay(descriptor, len(businessDay), loc, p.calendar)
+	}
+
+	return nil, atToken(parseErrorf(ReasonUnknownDescriptor, "unrecognized descriptor: %s", descriptor), descriptor, 0)
+}
+
+// parseEvery解析"@every"描述符的参数（从descriptor[offset:]开始）：
+//   duration [ "from" hour ":" minute [ ":" second ] ]
+//   duration [ "anchored" time ]
+// "from"表示每天从该时刻（按loc）开始按duration排列，"anchored"表示从给定时间开始排列；
+// 不带时区的时间按loc解释。与单独的duration一样，带有锚点的duration精确到毫秒，不舍入到秒。
+func parseEvery(descriptor string, offset int, loc *time.Location) (Schedule, error) {
+	args, offsets := splitFields(descriptor[offset:])
+	if len(args) != 1 && len(args) != 3 {
+		return nil, atToken(parseErrorf(ReasonFieldCount, "expected @every duration [from hh:mm | anchored time]: %s", descriptor), descriptor, 0)
+	}
+
+	duration, err := time.ParseDuration(args[0])
+	if err != nil {
+		return nil, atToken(parseErrorf(ReasonSyntax, "failed to parse duration %s: %s", descriptor, err), args[0], offset+offsets[0])
+	}
+	if len(args) == 1 {
+		// 带有不足一秒部分的间隔（例如"250ms"或"1.5s"）不再舍入到秒。
+		if duration%time.Second != 0 {
+			return EveryPrecisely(duration), nil
 		}
 		return Every(duration), nil
 	}
 
-	return nil, fmt.Errorf("unrecognized descriptor: %s", descriptor)
+	anchor, anchorOffset := args[2], offset+offsets[2]
+	switch args[1] {
+	case "from":
+		at, err := time.Parse("15:04", anchor)
+		if err != nil {
+			at, err = time.Parse("15:04:05", anchor)
+		}
+		if err != nil {
+			return nil, atToken(parseErrorf(ReasonSyntax, "failed to parse time of day %s", anchor), anchor, anchorOffset)
+		}
+		if duration > 24*time.Hour {
+			return nil, atToken(parseErrorf(ReasonOutOfRange, "duration must not exceed 24h when anchored to a time of day: %s", args[0]), args[0], offset+offsets[0])
+		}
+		return AnchoredDelaySchedule{
+			Delay:  EveryPrecisely(duration).Delay,
+			Anchor: time.Date(2000, time.January, 1, at.Hour(), at.Minute(), at.Second(), 0, loc),
+			Daily:  true,
+		}, nil
+	case "anchored":
+		t, err := parseIntervalTime(anchor, loc)
+		if err != nil {
+			return nil, atToken(err, anchor, anchorOffset)
+		}
+		return AnchoredDelaySchedule{Delay: EveryPrecisely(duration).Delay, Anchor: t}, nil
+	}
+	return nil, atToken(parseErrorf(ReasonSyntax, "expected from or anchored: %s", args[1]), args[1], offset+offsets[1])
+}
+
+// parseBusinessDay解析"@businessday"描述符的参数（从descriptor[offset:]开始）：
+//   number [ hour ":" minute ]
+// 其中number为非零整数，负数表示从月末倒数。
+func parseBusinessDay(descriptor string, offset int, loc *time.Location, cal *Calendar) (Schedule, error) {
+	args, offsets := splitFields(descriptor[offset:])
+	if len(args) == 0 || len(args) > 2 {
+		return nil, atToken(parseErrorf(ReasonFieldCount, "expected a business day and an optional time: %s", descriptor), descriptor, 0)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n == 0 || n > 31 || n < -31 {
+		return nil, atToken(parseErrorf(ReasonOutOfRange, "business day must be a non-zero number between -31 and 31: %s", descriptor), args[0], offset+offsets[0])
+	}
+
+	schedule := BusinessDaySchedule{N: n, Calendar: cal, Location: loc}
+	if len(args) == 2 {
+		at, err := time.Parse("15:04", args[1])
+		if err != nil {
+			return nil, atToken(parseErrorf(ReasonSyntax, "failed to parse time of day %s: %s", args[1], err), args[1], offset+offsets[1])
+		}
+		schedule.Hour, schedule.Minute = at.Hour(), at.Minute()
+	}
+	return schedule, nil
 }
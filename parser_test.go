@@ -29,6 +29,14 @@ func TestRange(t *testing.T) {
 		{"5-7/2", 0, 7, 1<<5 | 1<<7, ""},
 		{"5-7/1", 0, 7, 1<<5 | 1<<6 | 1<<7, ""},
 
+		{"6-1", 0, 7, 1<<6 | 1<<7 | 1<<0 | 1<<1, ""},
+		{"7-0", 0, 7, 1<<7 | 1<<0, ""},
+		{"5-3", 3, 5, 1<<5 | 1<<3, ""},
+		{"22-4/2", 0, 23, 1<<22 | 1<<0 | 1<<2 | 1<<4, ""},
+		{"22-4/3", 0, 23, 1<<22 | 1<<1 | 1<<4, ""},
+		{"30-3/2", 1, 31, 1<<30 | 1<<1 | 1<<3, ""},
+		{"5-1/7", 0, 7, 1 << 5, ""},
+
 		{"*", 1, 3, 1<<1 | 1<<2 | 1<<3 | starBit, ""},
 		{"*/2", 1, 3, 1<<1 | 1<<3, ""},
 
@@ -39,12 +47,12 @@ func TestRange(t *testing.T) {
 		{"*//2", 0, 0, zero, "too many slashes"},
 		{"1", 3, 5, zero, "below minimum"},
 		{"6", 3, 5, zero, "above maximum"},
-		{"5-3", 3, 5, zero, "beyond end of range"},
+		{"4-2", 3, 5, zero, "below minimum"},
 		{"*/0", 0, 0, zero, "should be a positive number"},
 	}
 
 	for _, c := range ranges {
-		actual, err := getRange(c.expr, bounds{c.min, c.max, nil})
+		actual, err := getRange(c.expr, bounds{c.min, c.max, nil}, nil)
 		if len(c.err) != 0 && (err == nil || !strings.Contains(err.Error(), c.err)) {
 			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
 		}
@@ -57,6 +65,55 @@ func TestRange(t *testing.T) {
 	}
 }
 
+func TestHashRange(t *testing.T) {
+	hash := uint64(7)
+	zero := uint64(0)
+	ranges := []struct {
+		expr     string
+		min, max uint
+		expected uint64
+		err      string
+	}{
+		{"H", 0, 59, 1 << 7, ""},
+		{"H", 1, 5, 1 << 3, ""},
+		{"H(10-19)", 0, 59, 1 << 17, ""},
+		{"H/20", 0, 59, 1<<7 | 1<<27 | 1<<47, ""},
+		{"H(0-3)/10", 0, 59, 1 << 3, ""},
+		{"H(20-40)/10", 0, 59, 1<<27 | 1<<37, ""},
+
+		// Hash ranges wrap around like plain ranges.
+		{"H(5-3)", 0, 59, 1 << 12, ""},
+		{"H(20-1)", 0, 23, 1 << 21, ""},
+		{"H(22-2)", 0, 23, 1 << 0, ""},
+		{"H(22-2)/2", 0, 23, 1<<23 | 1<<1, ""},
+
+		{"H(x-3)", 0, 59, zero, "failed to parse int from"},
+		{"H(1)", 0, 59, zero, "of the form H(low-high)"},
+		{"H1-3", 0, 59, zero, "malformed hash expression"},
+		{"H(0-60)", 0, 59, zero, "above maximum"},
+		{"H(3-0)", 1, 5, zero, "below minimum"},
+		{"H/0", 0, 59, zero, "should be a positive number"},
+		{"H/2/2", 0, 59, zero, "too many slashes"},
+	}
+
+	for _, c := range ranges {
+		actual, err := getRange(c.expr, bounds{c.min, c.max, nil}, &hash)
+		if len(c.err) != 0 && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+		if len(c.err) == 0 && err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+		}
+		if actual != c.expected {
+			t.Errorf("%s => expected %b, got %b", c.expr, c.expected, actual)
+		}
+	}
+
+	if _, err := getRange("H", minutes, nil); err == nil || !strings.Contains(err.Error(), "require a key") {
+		t.Errorf("expected an error for H without a key, got %v", err)
+	}
+}
+
 func TestField(t *testing.T) {
 	fields := []struct {
 		expr     string
@@ -70,7 +127,7 @@ func TestField(t *testing.T) {
 	}
 
 	for _, c := range fields {
-		actual, _ := getField(c.expr, bounds{c.min, c.max, nil})
+		actual, _ := getField(c.expr, bounds{c.min, c.max, nil}, nil)
 		if actual != c.expected {
 			t.Errorf("%s => expected %d, got %d", c.expr, c.expected, actual)
 		}
@@ -203,7 +260,7 @@ func TestOptionalSecondSchedule(t *testing.T) {
 	}
 }
 
-func TestNormalizeFields(t *testing.T) {
+func TestExpandFields(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    []string
@@ -214,60 +271,90 @@ func TestNormalizeFields(t *testing.T) {
 			"AllFields_NoOptional",
 			[]string{"0", "5", "*", "*", "*", "*"},
 			Second | Minute | Hour | Dom | Month | Dow | Descriptor,
-			[]string{"0", "5", "*", "*", "*", "*"},
+			[]string{"0", "5", "*", "*", "*", "*", "*", "0"},
 		},
 		{
 			"AllFields_SecondOptional_Provided",
 			[]string{"0", "5", "*", "*", "*", "*"},
 			SecondOptional | Minute | Hour | Dom | Month | Dow | Descriptor,
-			[]string{"0", "5", "*", "*", "*", "*"},
+			[]string{"0", "5", "*", "*", "*", "*", "*", "0"},
 		},
 		{
 			"AllFields_SecondOptional_NotProvided",
 			[]string{"5", "*", "*", "*", "*"},
 			SecondOptional | Minute | Hour | Dom | Month | Dow | Descriptor,
-			[]string{"0", "5", "*", "*", "*", "*"},
+			[]string{"0", "5", "*", "*", "*", "*", "*", "0"},
 		},
 		{
 			"SubsetFields_NoOptional",
 			[]string{"5", "15", "*"},
 			Hour | Dom | Month,
-			[]string{"0", "0", "5", "15", "*", "*"},
+			[]string{"0", "0", "5", "15", "*", "*", "*", "0"},
 		},
 		{
 			"SubsetFields_DowOptional_Provided",
 			[]string{"5", "15", "*", "4"},
 			Hour | Dom | Month | DowOptional,
-			[]string{"0", "0", "5", "15", "*", "4"},
+			[]string{"0", "0", "5", "15", "*", "4", "*", "0"},
 		},
 		{
 			"SubsetFields_DowOptional_NotProvided",
 			[]string{"5", "15", "*"},
 			Hour | Dom | Month | DowOptional,
-			[]string{"0", "0", "5", "15", "*", "*"},
+			[]string{"0", "0", "5", "15", "*", "*", "*", "0"},
 		},
 		{
 			"SubsetFields_SecondOptional_NotProvided",
 			[]string{"5", "15", "*"},
 			SecondOptional | Hour | Dom | Month,
-			[]string{"0", "0", "5", "15", "*", "*"},
+			[]string{"0", "0", "5", "15", "*", "*", "*", "0"},
+		},
+		{
+			"AllFields_Year",
+			[]string{"0", "0", "1", "1", "*", "2027"},
+			Minute | Hour | Dom | Month | Dow | Year,
+			[]string{"0", "0", "0", "1", "1", "*", "2027", "0"},
+		},
+		{
+			"AllFields_YearOptional_Provided",
+			[]string{"0", "0", "1", "1", "*", "2027"},
+			Minute | Hour | Dom | Month | Dow | YearOptional,
+			[]string{"0", "0", "0", "1", "1", "*", "2027", "0"},
+		},
+		{
+			"AllFields_YearOptional_NotProvided",
+			[]string{"0", "0", "1", "1", "*"},
+			Minute | Hour | Dom | Month | Dow | YearOptional,
+			[]string{"0", "0", "0", "1", "1", "*", "*", "0"},
+		},
+		{
+			"AllFields_Millisecond",
+			[]string{"*/250", "0", "5", "*", "*", "*", "*"},
+			Millisecond | Second | Minute | Hour | Dom | Month | Dow,
+			[]string{"0", "5", "*", "*", "*", "*", "*", "*/250"},
+		},
+		{
+			"AllFields_MillisecondOptional_NotProvided",
+			[]string{"0", "5", "*", "*", "*", "*"},
+			MillisecondOptional | Second | Minute | Hour | Dom | Month | Dow,
+			[]string{"0", "5", "*", "*", "*", "*", "*", "0"},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := normalizeFields(test.input, test.options)
+			indexes, err := fieldIndexes(len(test.input), test.options)
 			if err != nil {
-				t.Errorf("unexpected error: %v", err)
+				t.Fatalf("unexpected error: %v", err)
 			}
-			if !reflect.DeepEqual(actual, test.expected) {
+			if actual := expandFields(test.input, indexes); !reflect.DeepEqual(actual, test.expected) {
 				t.Errorf("expected %v, got %v", test.expected, actual)
 			}
 		})
 	}
 }
 
-func TestNormalizeFields_Errors(t *testing.T) {
+func TestFieldIndexes_Errors(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   []string
@@ -301,9 +388,9 @@ func TestNormalizeFields_Errors(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := normalizeFields(test.input, test.options)
+			actual, err := fieldIndexes(len(test.input), test.options)
 			if err == nil {
-				t.Errorf("expected an error, got none. results: %v", actual)
+				t.Fatalf("expected an error, got none. results: %v", actual)
 			}
 			if !strings.Contains(err.Error(), test.err) {
 				t.Errorf("expected error %q, got %q", test.err, err.Error())
@@ -319,13 +406,25 @@ func TestStandardSpecSchedule(t *testing.T) {
 		err      string
 	}{
 		{
-			expr:     "5 * * * *",
-			expected: &SpecSchedule{1 << seconds.min, 1 << 5, all(hours), all(dom), all(months), all(dow), time.Local},
+			expr: "5 * * * *",
+			expected: &SpecSchedule{
+				Second:   1 << seconds.min,
+				Minute:   1 << 5,
+				Hour:     all(hours),
+				Dom:      all(dom),
+				Month:    all(months),
+				Dow:      all(dow),
+				Location: time.Local,
+			},
 		},
 		{
 			expr:     "@every 5m",
 			expected: ConstantDelaySchedule{time.Duration(5) * time.Minute},
 		},
+		{
+			expr:     "@reboot",
+			expected: RebootSchedule{},
+		},
 		{
 			expr: "5 j * * *",
 			err:  "failed to parse int from",
@@ -358,16 +457,235 @@ func TestNoDescriptorParser(t *testing.T) {
 	}
 }
 
+func TestQuartzModifiers(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | QuartzModifiers)
+	entries := []struct {
+		expr     string
+		expected *SpecSchedule
+		err      string
+	}{
+		{expr: "0 0 L * *", expected: &SpecSchedule{Dow: all(dow), DomLast: 1}},
+		{expr: "0 0 L-3,l * *", expected: &SpecSchedule{Dow: all(dow), DomLast: 1<<3 | 1}},
+		{expr: "0 0 LW * *", expected: &SpecSchedule{Dow: all(dow), DomWeekday: 1}},
+		{expr: "0 0 15W,1 * *", expected: &SpecSchedule{Dom: 1 << 1, Dow: all(dow), DomWeekday: 1 << 15}},
+		{expr: "0 0 * * 5L", expected: &SpecSchedule{Dom: all(dom), DowLast: 1 << 5}},
+		{expr: "0 0 ? * FRIL", expected: &SpecSchedule{Dom: all(dom), DowLast: 1 << 5}},
+		{expr: "0 0 * * mon#2,sun#1", expected: &SpecSchedule{Dom: all(dom), DowNth: 1<<(7+1) | 1<<0}},
+		{expr: "0 0 * * 6#5,3", expected: &SpecSchedule{Dom: all(dom), Dow: 1 << 3, DowNth: 1 << (4*7 + 6)}},
+		{expr: "0 0 ? * L", expected: &SpecSchedule{Dom: all(dom), Dow: 1 << 6}},
+		{expr: "0 0 ? * l,mon", expected: &SpecSchedule{Dom: all(dom), Dow: 1<<6 | 1<<1}},
+
+		{expr: "0 0 L-31 * *", err: "above maximum"},
+		{expr: "0 0 32W * *", err: "out of range"},
+		{expr: "0 0 xW * *", err: "failed to parse int from"},
+		{expr: "0 0 * * 7L", err: "above maximum"},
+		{expr: "0 0 * * 1#6", err: "must be between 1 and 5"},
+		{expr: "0 0 * * 1#0", err: "must be between 1 and 5"},
+		{expr: "0 0 * * 1#x", err: "failed to parse int from"},
+	}
+
+	for _, c := range entries {
+		actual, err := parser.Parse(c.expr)
+		if len(c.err) != 0 {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		c.expected.Second = 1 << seconds.min
+		c.expected.Minute = 1 << minutes.min
+		c.expected.Hour = 1 << hours.min
+		c.expected.Month = all(months)
+		c.expected.Location = time.Local
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s => expected %+v, got %+v", c.expr, c.expected, actual)
+		}
+	}
+
+	// Without the option, modifiers are rejected.
+	if _, err := standardParser.Parse("0 0 L * *"); err == nil {
+		t.Error("expected an error parsing modifiers without QuartzModifiers")
+	}
+}
+
+func TestDayModes(t *testing.T) {
+	var (
+		vixie  = NewParser(Minute | Hour | Dom | Month | Dow)
+		and    = NewParser(Minute | Hour | Dom | Month | Dow | DayAnd)
+		quartz = NewParser(Minute | Hour | Dom | Month | Dow | DayQuartz)
+	)
+	tests := []struct {
+		parser    Parser
+		spec      string
+		domAndDow bool
+		err       string
+	}{
+		{vixie, "0 0 13 * fri", false, ""},
+		{vixie, "0 0 13 * ?", false, ""},
+		{and, "0 0 13 * fri", true, ""},
+		{and, "0 0 ? * fri", true, ""},
+		{quartz, "0 0 13 * ?", false, ""},
+		{quartz, "0 0 ? * fri", false, ""},
+		{quartz, "0 0 13 * fri", false, "exactly one of day-of-month and day-of-week must be ?"},
+		{quartz, "0 0 * * *", false, "exactly one of day-of-month and day-of-week must be ?"},
+		{quartz, "0 0 ? * ?", false, "exactly one of day-of-month and day-of-week must be ?"},
+	}
+	for _, test := range tests {
+		actual, err := test.parser.Parse(test.spec)
+		if test.err != "" {
+			perr, ok := err.(*ParseError)
+			if !ok || !strings.Contains(err.Error(), test.err) || perr.Field != "day of week" || perr.Offset != strings.LastIndex(test.spec, " ")+1 {
+				t.Errorf("%s => expected %q on the day of week, got %v", test.spec, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		if spec := actual.(*SpecSchedule); spec.DomAndDow != test.domAndDow {
+			t.Errorf("%s => expected DomAndDow %v, got %v", test.spec, test.domAndDow, spec.DomAndDow)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic configuring DayAnd with DayQuartz")
+		}
+	}()
+	NewParser(Minute | Hour | Dom | Month | Dow | DayAnd | DayQuartz)
+}
+
+func TestYearField(t *testing.T) {
+	fields := []struct {
+		expr     string
+		expected []int
+		err      string
+	}{
+		{"*", nil, ""},
+		{"?", nil, ""},
+		{"2027", []int{2027}, ""},
+		{"2029,2027-2028", []int{2027, 2028, 2029}, ""},
+		{"2020-2030/5", []int{2020, 2025, 2030}, ""},
+		{"2095/2", []int{2095, 2097, 2099}, ""},
+		{"2027,*", nil, ""},
+
+		{"1969", nil, "below minimum"},
+		{"2100", nil, "above maximum"},
+		{"2029-2027", nil, "beyond end of range"},
+		{"x", nil, "failed to parse int from"},
+	}
+
+	for _, c := range fields {
+		actual, err := getYearField(c.expr)
+		if len(c.err) != 0 && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.err, err)
+		}
+		if len(c.err) == 0 && err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.expected, actual)
+		}
+	}
+}
+
+func TestParseYear(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | YearOptional)
+	entries := []struct {
+		expr     string
+		expected []int
+	}{
+		{"0 0 1 1 *", nil},
+		{"0 0 1 1 * *", nil},
+		{"0 0 1 1 * 2027-2029", []int{2027, 2028, 2029}},
+	}
+
+	for _, c := range entries {
+		actual, err := parser.Parse(c.expr)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		if years := actual.(*SpecSchedule).Year; !reflect.DeepEqual(years, c.expected) {
+			t.Errorf("%s => expected %v, got %v", c.expr, c.expected, years)
+		}
+	}
+
+	if _, err := standardParser.Parse("0 0 1 1 * 2027"); err == nil {
+		t.Error("expected an error parsing a year without the Year option")
+	}
+}
+
+func TestParseWithKey(t *testing.T) {
+	entries := []struct {
+		expr, key    string
+		minute, hour uint64
+	}{
+		// The values for a given key must never change between releases.
+		{"H H(0-7) * * *", "nightly-report", 1 << 26, 1 << 3},
+		{"H H(0-7) * * *", "billing", 1 << 39, 1 << 2},
+		{"H/15 * * * *", "nightly-report", 1<<11 | 1<<26 | 1<<41 | 1<<56, all(hours)},
+		{"H/15 * * * *", "billing", 1<<9 | 1<<24 | 1<<39 | 1<<54, all(hours)},
+		{"0 H * * *", "billing", 1 << 0, 1 << 10},
+	}
+
+	for _, c := range entries {
+		actual, err := standardParser.ParseWithKey(c.expr, c.key)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", c.expr, err)
+			continue
+		}
+		s := actual.(*SpecSchedule)
+		if s.Minute != c.minute || s.Hour != c.hour {
+			t.Errorf("%s (%s) => expected minute %b hour %b, got minute %b hour %b",
+				c.expr, c.key, c.minute, c.hour, s.Minute, s.Hour)
+		}
+	}
+
+	if _, err := standardParser.Parse("H * * * *"); err == nil {
+		t.Error("expected an error parsing H without a key")
+	}
+}
+
 func every5min(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 0, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{
+		Second:   1 << 0,
+		Minute:   1 << 5,
+		Hour:     all(hours),
+		Dom:      all(dom),
+		Month:    all(months),
+		Dow:      all(dow),
+		Location: loc,
+	}
 }
 
 func every5min5s(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1 << 5, 1 << 5, all(hours), all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{
+		Second:   1 << 5,
+		Minute:   1 << 5,
+		Hour:     all(hours),
+		Dom:      all(dom),
+		Month:    all(months),
+		Dow:      all(dow),
+		Location: loc,
+	}
 }
 
 func midnight(loc *time.Location) *SpecSchedule {
-	return &SpecSchedule{1, 1, 1, all(dom), all(months), all(dow), loc}
+	return &SpecSchedule{
+		Second:   1,
+		Minute:   1,
+		Hour:     1,
+		Dom:      all(dom),
+		Month:    all(months),
+		Dow:      all(dow),
+		Location: loc,
+	}
 }
 
 func annual(loc *time.Location) *SpecSchedule {
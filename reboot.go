@@ -0,0 +1,30 @@
+package cron
+
+import "time"
+
+// RebootSchedule是在调度程序启动时激活一次的时间表，对应于crontab中的"@reboot"。
+// 它本身从不按时间激活，而是由Cron在启动时激活：每个条目在它遇到的第一次启动时激活，
+// 在Cron运行时加入的条目则立即激活。
+type RebootSchedule struct {
+	// EveryStart为true时，Stop之后的每次Start都会再激活一次。
+	EveryStart bool
+}
+
+// Next总是返回时间的零值，启动时的激活由Cron负责。
+func (RebootSchedule) Next(time.Time) time.Time {
+	return time.Time{}
+}
+
+// String返回"@reboot"。
+func (RebootSchedule) String() string {
+	return "@reboot"
+}
+
+// firstNext返回条目在调度程序启动或条目加入运行中的调度程序时的激活时间。
+func firstNext(e *Entry, now time.Time) time.Time {
+	if r, ok := e.Schedule.(RebootSchedule); ok && (r.EveryStart || !e.started) {
+		e.started = true
+		return now
+	}
+	return e.Schedule.Next(now)
+}
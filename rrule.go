@@ -0,0 +1,752 @@
+package cron
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Frequency是RRULE中FREQ的取值，即重复的周期。
+type Frequency int
+
+const (
+	Secondly Frequency = iota
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = []string{"SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+func (f Frequency) String() string {
+	if f >= 0 && int(f) < len(frequencyNames) {
+		return frequencyNames[f]
+	}
+	return fmt.Sprintf("Frequency(%d)", int(f))
+}
+
+// WeekdayNum是BYDAY中的一项，例如"MO"、"1MO"（第一个周一）或"-1FR"（最后一个周五）。
+// N为0时表示周期内的每个该星期几。
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// RRuleSchedule是RFC 5545中重复规则（RRULE）所描述的时间表。
+//
+// 每个周期（由Freq和Interval决定，从Start开始）内，BYxxx规则先扩展或限制候选时间，
+// 再由BySetPos选出其中的几个。Start之前的时间、Until之后的时间以及Count之外的时间会被丢弃，
+// Exclude中的时间（EXDATE）会被跳过，但仍然计入Count。
+//
+// 带Count的规则会记录已经数过的激活次数，因此第一次调用Next之后不应再修改它的字段。
+type RRuleSchedule struct {
+	Freq     Frequency
+	Interval int       // 周期的间隔，0视为1
+	Count    int       // 最多激活的次数，0表示不限
+	Until    time.Time // 最后一次激活的上限（包含），零值表示不限
+
+	ByMonth    []int        // 1至12
+	ByWeekNo   []int        // ±1至53，只用于Yearly
+	ByYearDay  []int        // ±1至366
+	ByMonthDay []int        // ±1至31
+	ByDay      []WeekdayNum // 带序号的项只用于Monthly和Yearly
+	ByHour     []int        // 0至23
+	ByMinute   []int        // 0至59
+	BySecond   []int        // 0至59
+	BySetPos   []int        // ±1至366
+
+	WeekStart time.Weekday // 一周的第一天（WKST），用于Weekly和ByWeekNo
+
+	// Start是第一个周期的起点（DTSTART），它的时区也是所有激活时间的时区。
+	// 未指定的BYxxx规则从Start中取值，例如FREQ=DAILY在Start的时刻激活。
+	Start time.Time
+
+	// Exclude是要跳过的激活时间（EXDATE）。
+	Exclude []time.Time
+
+	counted rruleCounted
+}
+
+// rruleCounted记录已经数过的周期：前k个周期内共有count次激活（包括被排除的）。
+// 这样按时间顺序调用Next时，只需要数上一次调用之后的周期，而不必每次都从第一个周期开始。
+type rruleCounted struct {
+	mu       sync.Mutex
+	k, count int
+}
+
+// Next返回晚于t的下一个激活时间。
+// 如果规则已经结束，或者在五年内（或MaxActivations个周期内）都没有激活时间，则返回时间的零值。
+func (r *RRuleSchedule) Next(t time.Time) time.Time {
+	// 直接跳到t附近的周期；有Count时还要知道此前的周期内已经激活了多少次。
+	k, count := r.periodsBefore(t), 0
+	if r.Count > 0 {
+		var remaining bool
+		if count, remaining = r.countBefore(k); !remaining {
+			return time.Time{}
+		}
+	}
+
+	limit := t.AddDate(compositeYears, 0, 0)
+	if end := r.periodStart(k + compositeYears); end.After(limit) {
+		limit = end
+	}
+
+	for scanned := 0; scanned < MaxActivations; scanned, k = scanned+1, k+1 {
+		start := r.periodStart(k)
+		if start.After(limit) || !r.Until.IsZero() && start.After(r.Until) {
+			break
+		}
+		for _, next := range r.occurrences(start) {
+			if next.Before(r.Start) {
+				continue
+			}
+			if !r.Until.IsZero() && next.After(r.Until) {
+				return time.Time{}
+			}
+			if count++; r.Count > 0 && count > r.Count {
+				return time.Time{}
+			}
+			if next.After(t) && !r.excluded(next) {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// countBefore返回前k个周期内的激活次数（包括被排除的），以及此后是否还有剩余的激活。
+// 次数达到Count，或连续MaxActivations个周期都没有激活时，不再有剩余的激活。
+func (r *RRuleSchedule) countBefore(k int) (count int, remaining bool) {
+	r.counted.mu.Lock()
+	defer r.counted.mu.Unlock()
+
+	i := 0
+	if r.counted.k <= k {
+		i, count = r.counted.k, r.counted.count
+	}
+	for empty := 0; i < k && count < r.Count; i++ {
+		if empty++; empty > MaxActivations {
+			return count, false
+		}
+		for _, next := range r.occurrences(r.periodStart(i)) {
+			if !next.Before(r.Start) {
+				count, empty = count+1, 0
+			}
+		}
+	}
+	if i > r.counted.k {
+		r.counted.k, r.counted.count = i, count
+	}
+	return count, count < r.Count
+}
+
+func (r *RRuleSchedule) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// periodStart返回第k个周期的起点。
+func (r *RRuleSchedule) periodStart(k int) time.Time {
+	var (
+		s           = r.Start
+		n           = k * r.interval()
+		y, m, d     = s.Date()
+		h, min, sec = s.Clock()
+		loc         = s.Location()
+	)
+	switch r.Freq {
+	case Yearly:
+		return time.Date(y+n, 1, 1, 0, 0, 0, 0, loc)
+	case Monthly:
+		return time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, loc)
+	case Weekly:
+		back := (int(s.Weekday()) - int(r.WeekStart) + 7) % 7
+		return time.Date(y, m, d-back+7*n, 0, 0, 0, 0, loc)
+	case Daily:
+		return time.Date(y, m, d+n, 0, 0, 0, 0, loc)
+	case Hourly:
+		return time.Date(y, m, d, h+n, 0, 0, 0, loc)
+	case Minutely:
+		return time.Date(y, m, d, h, min+n, 0, 0, loc)
+	default:
+		return time.Date(y, m, d, h, min, sec+n, 0, loc)
+	}
+}
+
+// periodsBefore返回一个不晚于t所在周期的周期序号（可能偏早），用于跳过t之前的周期。
+func (r *RRuleSchedule) periodsBefore(t time.Time) int {
+	if !t.After(r.Start) {
+		return 0
+	}
+	t = t.In(r.Start.Location())
+	var n int
+	switch r.Freq {
+	case Yearly:
+		n = t.Year() - r.Start.Year()
+	case Monthly:
+		n = (t.Year()-r.Start.Year())*12 + int(t.Month()) - int(r.Start.Month())
+	case Weekly:
+		n = daysBetween(r.Start, t) / 7
+	case Daily:
+		n = daysBetween(r.Start, t)
+	case Hourly:
+		n = int(t.Sub(r.Start) / time.Hour)
+	case Minutely:
+		n = int(t.Sub(r.Start) / time.Minute)
+	default:
+		n = int(t.Sub(r.Start) / time.Second)
+	}
+	// 夏令时可能使估计偏大，因此多退一个周期。
+	if n = n/r.interval() - 1; n < 0 {
+		return 0
+	}
+	return n
+}
+
+// daysBetween返回从a所在的日历日到b所在的日历日的天数。
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return int(time.Date(by, bm, bd, 12, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 12, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+}
+
+// occurrences返回从start开始的周期内的所有候选时间（升序），已经应用了BySetPos。
+func (r *RRuleSchedule) occurrences(start time.Time) []time.Time {
+	var (
+		loc     = start.Location()
+		hours   = r.timeValues(r.ByHour, Hourly, start.Hour(), r.Start.Hour())
+		minutes = r.timeValues(r.ByMinute, Minutely, start.Minute(), r.Start.Minute())
+		seconds = r.timeValues(r.BySecond, Secondly, start.Second(), r.Start.Second())
+		times   []time.Time
+	)
+	for _, day := range r.days(start) {
+		for _, h := range hours {
+			for _, min := range minutes {
+				for _, sec := range seconds {
+					times = append(times, time.Date(day.Year(), day.Month(), day.Day(), h, min, sec, 0, loc))
+				}
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	if len(r.BySetPos) == 0 {
+		return times
+	}
+	var selected []time.Time
+	for i, t := range times {
+		for _, pos := range r.BySetPos {
+			if pos == i+1 || pos == i-len(times) {
+				selected = append(selected, t)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// timeValues返回周期内小时（或分钟、秒）的取值。比该字段更粗的周期用values扩展，
+// 未指定时取Start中的值；不比该字段粗的周期只有周期本身的值，values只用于限制。
+func (r *RRuleSchedule) timeValues(values []int, freq Frequency, period, start int) []int {
+	if r.Freq > freq {
+		if len(values) == 0 {
+			return []int{start}
+		}
+		return values
+	}
+	if len(values) == 0 || containsInt(values, period) {
+		return []int{period}
+	}
+	return nil
+}
+
+// days返回周期内满足所有日期规则的日期（零点）。
+func (r *RRuleSchedule) days(start time.Time) []time.Time {
+	var first, count = start, 1
+	switch r.Freq {
+	case Yearly:
+		count = daysInYear(start.Year())
+	case Monthly:
+		count = daysIn(start.Year(), start.Month())
+	case Weekly:
+		count = 7
+	default:
+		first = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	}
+
+	var days []time.Time
+	for i := 0; i < count; i++ {
+		day := time.Date(first.Year(), first.Month(), first.Day()+i, 0, 0, 0, 0, first.Location())
+		if r.dayMatches(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// dayMatches报告day是否满足所有日期规则，包括从Start推断出的规则。
+func (r *RRuleSchedule) dayMatches(day time.Time) bool {
+	var (
+		year, month, mday = day.Date()
+		yday              = day.YearDay()
+		monthDays         = daysIn(year, month)
+		yearDays          = daysInYear(year)
+	)
+	if len(r.ByMonth) > 0 && !containsInt(r.ByMonth, int(month)) {
+		return false
+	}
+	if len(r.ByWeekNo) > 0 {
+		week, weeks := weekNumber(day, r.WeekStart)
+		if !containsInt(r.ByWeekNo, week) && !containsInt(r.ByWeekNo, week-weeks-1) {
+			return false
+		}
+	}
+	if len(r.ByYearDay) > 0 && !containsInt(r.ByYearDay, yday) && !containsInt(r.ByYearDay, yday-yearDays-1) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !containsInt(r.ByMonthDay, mday) && !containsInt(r.ByMonthDay, mday-monthDays-1) {
+		return false
+	}
+	if len(r.ByDay) > 0 {
+		// 带序号的项在Monthly（或指定了ByMonth的Yearly）中按月计数，否则按年计数。
+		index, total := mday, monthDays
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			index, total = yday, yearDays
+		}
+		matched := false
+		for _, wd := range r.ByDay {
+			if wd.Weekday != day.Weekday() {
+				continue
+			}
+			if wd.N == 0 || wd.N == (index-1)/7+1 || wd.N == -((total-index)/7+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	// 没有给出扩展日期的规则时，日期由Start决定。
+	switch r.Freq {
+	case Yearly:
+		if len(r.ByWeekNo) == 0 && len(r.ByYearDay) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+			if mday != r.Start.Day() || len(r.ByMonth) == 0 && month != r.Start.Month() {
+				return false
+			}
+		}
+	case Monthly:
+		if len(r.ByMonthDay) == 0 && len(r.ByYearDay) == 0 && len(r.ByDay) == 0 && mday != r.Start.Day() {
+			return false
+		}
+	case Weekly:
+		if len(r.ByDay) == 0 && day.Weekday() != r.Start.Weekday() {
+			return false
+		}
+	}
+	return true
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// weekNumber返回day所在的周数（按RFC 5545：每周从weekStart开始，第1周是第一个在该年中至少有4天的周），
+// 以及该周所属年份的总周数。
+func weekNumber(day time.Time, weekStart time.Weekday) (week, weeks int) {
+	year := day.Year()
+	first := firstWeek(year, weekStart)
+	if day.Before(first) {
+		year--
+		first = firstWeek(year, weekStart)
+	} else if next := firstWeek(year+1, weekStart); !day.Before(next) {
+		year++
+		first = next
+	}
+	weeks = daysBetween(first, firstWeek(year+1, weekStart)) / 7
+	return daysBetween(first, day)/7 + 1, weeks
+}
+
+// firstWeek返回year年第1周的第一天。
+func firstWeek(year int, weekStart time.Weekday) time.Time {
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan1.Weekday()) - int(weekStart) + 7) % 7
+	if offset <= 3 {
+		return jan1.AddDate(0, 0, -offset)
+	}
+	return jan1.AddDate(0, 0, 7-offset)
+}
+
+func (r *RRuleSchedule) excluded(t time.Time) bool {
+	for _, ex := range r.Exclude {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRRule解析RFC 5545格式的重复规则，浮动时间（不带"Z"或TZID）按time.Local解释。
+// text由空白或换行分隔的若干属性组成：
+//
+//  [ "DTSTART" [ ";TZID=" zone | ";VALUE=DATE" ] ":" date-time ]
+//  "RRULE:" rule-part *( ";" rule-part )
+//  *( "EXDATE" [ params ] ":" date-time *( "," date-time ) )
+//
+// 例如"DTSTART:20260105T090000Z RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"。
+// 没有DTSTART时，规则从解析时的当前时间（精确到秒）开始。
+//
+// 启用了RRule选项的Parser也接受以"RRULE:"或"DTSTART"开头的spec，
+// 因此使用这种Parser的Cron可以直接通过AddFunc添加这些规则。
+func ParseRRule(text string) (*RRuleSchedule, error) {
+	schedule, err := parseRRule(text, time.Local)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = text
+	}
+	return schedule, err
+}
+
+// isRRule报告spec是否为RRULE（以"RRULE:"或"DTSTART"开头）。
+func isRRule(spec string) bool {
+	upper := strings.ToUpper(spec)
+	return strings.HasPrefix(upper, "RRULE:") || strings.HasPrefix(upper, "DTSTART")
+}
+
+// parseRRule实现了ParseRRule，浮动时间按loc解释。
+func parseRRule(text string, loc *time.Location) (*RRuleSchedule, error) {
+	var (
+		r                 = &RRuleSchedule{WeekStart: time.Monday}
+		rule              string
+		ruleOffset        int
+		hasRule, hasStart bool
+	)
+
+	props, offsets := splitFields(text)
+	for i, prop := range props {
+		colon := strings.Index(prop, ":")
+		if colon < 0 {
+			return nil, atToken(parseErrorf(ReasonSyntax, "expected NAME:VALUE: %s", prop), prop, offsets[i])
+		}
+		params := strings.Split(prop[:colon], ";")
+		value, valueOffset := prop[colon+1:], offsets[i]+colon+1
+
+		switch strings.ToUpper(params[0]) {
+		case "RRULE":
+			if hasRule {
+				return nil, atToken(parseErrorf(ReasonUnsupported, "multiple RRULE properties are not supported"), prop, offsets[i])
+			}
+			hasRule, rule, ruleOffset = true, value, valueOffset
+		case "DTSTART":
+			start, err := parseICalTime(value, params[1:], loc)
+			if err != nil {
+				return nil, atToken(err, value, valueOffset)
+			}
+			hasStart, r.Start = true, start
+		case "EXDATE":
+			offset := valueOffset
+			for _, v := range strings.Split(value, ",") {
+				ex, err := parseICalTime(v, params[1:], loc)
+				if err != nil {
+					return nil, atToken(err, v, offset)
+				}
+				r.Exclude = append(r.Exclude, ex)
+				offset += len(v) + 1
+			}
+		default:
+			return nil, atToken(parseErrorf(ReasonSyntax, "unknown property: %s", params[0]), prop, offsets[i])
+		}
+	}
+	if !hasRule {
+		return nil, atToken(parseErrorf(ReasonSyntax, "missing RRULE: %s", text), text, 0)
+	}
+	if !hasStart {
+		now := time.Now().In(loc)
+		r.Start = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, loc)
+	}
+
+	if err := r.parseRule(rule, r.Start.Location()); err != nil {
+		return nil, shiftError(err, ruleOffset)
+	}
+	return r, nil
+}
+
+// parseRule解析RRULE属性的值并校验各部分的组合，错误的位置相对于rule。
+func (r *RRuleSchedule) parseRule(rule string, loc *time.Location) error {
+	var (
+		hasFreq bool
+		offset  int
+	)
+	for _, part := range strings.Split(rule, ";") {
+		err := r.parseRulePart(part, loc, &hasFreq)
+		if err != nil {
+			return atToken(err, part, offset)
+		}
+		offset += len(part) + 1
+	}
+
+	switch {
+	case !hasFreq:
+		return atToken(parseErrorf(ReasonSyntax, "missing FREQ: %s", rule), rule, 0)
+	case r.Count > 0 && !r.Until.IsZero():
+		return atToken(parseErrorf(ReasonUnsupported, "COUNT and UNTIL may not both be specified: %s", rule), rule, 0)
+	case len(r.ByWeekNo) > 0 && r.Freq != Yearly:
+		return atToken(parseErrorf(ReasonUnsupported, "BYWEEKNO is only allowed with FREQ=YEARLY: %s", rule), rule, 0)
+	case len(r.ByYearDay) > 0 && (r.Freq == Daily || r.Freq == Weekly || r.Freq == Monthly):
+		return atToken(parseErrorf(ReasonUnsupported, "BYYEARDAY is not allowed with FREQ=%s: %s", r.Freq, rule), rule, 0)
+	case len(r.ByMonthDay) > 0 && r.Freq == Weekly:
+		return atToken(parseErrorf(ReasonUnsupported, "BYMONTHDAY is not allowed with FREQ=WEEKLY: %s", rule), rule, 0)
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return atToken(parseErrorf(ReasonUnsupported, "numbered BYDAY is only allowed with FREQ=MONTHLY or FREQ=YEARLY: %s", rule), rule, 0)
+		}
+	}
+	return nil
+}
+
+// parseRulePart解析RRULE中的一个"NAME=VALUE"部分。
+func (r *RRuleSchedule) parseRulePart(part string, loc *time.Location, hasFreq *bool) error {
+	eq := strings.Index(part, "=")
+	if eq < 0 {
+		return parseErrorf(ReasonSyntax, "expected NAME=VALUE: %s", part)
+	}
+	name, value := strings.ToUpper(part[:eq]), part[eq+1:]
+
+	var err error
+	switch name {
+	case "FREQ":
+		for f, fname := range frequencyNames {
+			if strings.EqualFold(value, fname) {
+				r.Freq, *hasFreq = Frequency(f), true
+				return nil
+			}
+		}
+		return parseErrorf(ReasonSyntax, "unknown frequency: %s", value)
+	case "INTERVAL":
+		r.Interval, err = parseRuleInt(value, 1, math.MaxInt32)
+	case "COUNT":
+		r.Count, err = parseRuleInt(value, 1, math.MaxInt32)
+	case "UNTIL":
+		r.Until, err = parseICalTime(value, nil, loc)
+		if err == nil && len(value) == len("20060102") {
+			// 只有日期的UNTIL包括当天的所有时间。
+			r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Second)
+		}
+	case "WKST":
+		wd, ok := rruleWeekdays[strings.ToUpper(value)]
+		if !ok {
+			return parseErrorf(ReasonSyntax, "unknown weekday: %s", value)
+		}
+		r.WeekStart = wd
+	case "BYMONTH":
+		r.ByMonth, err = parseRuleList(value, 1, 12, false)
+	case "BYWEEKNO":
+		r.ByWeekNo, err = parseRuleList(value, 1, 53, true)
+	case "BYYEARDAY":
+		r.ByYearDay, err = parseRuleList(value, 1, 366, true)
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseRuleList(value, 1, 31, true)
+	case "BYHOUR":
+		r.ByHour, err = parseRuleList(value, 0, 23, false)
+	case "BYMINUTE":
+		r.ByMinute, err = parseRuleList(value, 0, 59, false)
+	case "BYSECOND":
+		r.BySecond, err = parseRuleList(value, 0, 59, false)
+	case "BYSETPOS":
+		r.BySetPos, err = parseRuleList(value, 1, 366, true)
+	case "BYDAY":
+		for _, item := range strings.Split(value, ",") {
+			upper := strings.ToUpper(item)
+			if len(upper) < 2 {
+				return parseErrorf(ReasonSyntax, "malformed BYDAY: %s", item)
+			}
+			wd, ok := rruleWeekdays[upper[len(upper)-2:]]
+			if !ok {
+				return parseErrorf(ReasonSyntax, "unknown weekday: %s", item)
+			}
+			n := 0
+			if prefix := upper[:len(upper)-2]; prefix != "" {
+				if n, err = parseRuleInt(prefix, -53, 53); err != nil || n == 0 {
+					return parseErrorf(ReasonOutOfRange, "BYDAY ordinal must be between -53 and 53 and not zero: %s", item)
+				}
+			}
+			r.ByDay = append(r.ByDay, WeekdayNum{n, wd})
+		}
+	default:
+		return parseErrorf(ReasonUnsupported, "unsupported rule part: %s", name)
+	}
+	return err
+}
+
+// parseRuleInt解析[min，max]范围内的整数（可以带正负号）。
+func parseRuleInt(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, parseErrorf(ReasonSyntax, "failed to parse int from %s: %s", value, err)
+	}
+	if n < min || n > max {
+		return 0, parseErrorf(ReasonOutOfRange, "value (%d) out of range (%d-%d): %s", n, min, max, value)
+	}
+	return n, nil
+}
+
+// parseRuleList解析用逗号分隔的整数列表，每个值的绝对值在[min，max]范围内，
+// negative为true时允许负数（从末尾倒数）。结果按升序排列。
+func parseRuleList(value string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		lower := min
+		if negative {
+			lower = -max
+		}
+		n, err := parseRuleInt(item, lower, max)
+		if err != nil {
+			return nil, err
+		}
+		if negative && n > -min && n < min {
+			return nil, parseErrorf(ReasonOutOfRange, "value (%d) out of range (%d-%d or %d-%d): %s", n, -max, -min, min, max, item)
+		}
+		values = append(values, n)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// parseICalTime解析iCalendar的DATE或DATE-TIME值：以"Z"结尾的是UTC时间，
+// params中的TZID指定时区，否则按loc解释。
+func parseICalTime(value string, params []string, loc *time.Location) (time.Time, error) {
+	for _, param := range params {
+		if eq := strings.Index(param, "="); eq >= 0 && strings.EqualFold(param[:eq], "TZID") {
+			var err error
+			if loc, err = time.LoadLocation(param[eq+1:]); err != nil {
+				return time.Time{}, parseErrorf(ReasonBadLocation, "provided bad location %s: %v", param[eq+1:], err)
+			}
+		}
+	}
+
+	layout := "20060102T150405"
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		value, loc = value[:len(value)-1], time.UTC
+	case len(value) == len("20060102"):
+		layout = "20060102"
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, parseErrorf(ReasonSyntax, "failed to parse date-time %s: %s", value, err)
+	}
+	return t, nil
+}
+
+// String返回该规则的iCalendar表示，由空格分隔的DTSTART、RRULE和EXDATE组成，
+// 可以被ParseRRule或Parser再次解析。
+func (r *RRuleSchedule) String() string {
+	var parts []string
+	parts = append(parts, "FREQ="+r.Freq.String())
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	lists := []struct {
+		name   string
+		values []int
+	}{
+		{"BYMONTH", r.ByMonth},
+		{"BYWEEKNO", r.ByWeekNo},
+		{"BYYEARDAY", r.ByYearDay},
+		{"BYMONTHDAY", r.ByMonthDay},
+	}
+	for _, list := range lists {
+		if len(list.values) > 0 {
+			parts = append(parts, list.name+"="+joinInts(list.values))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, wd := range r.ByDay {
+			day := strings.ToUpper(wd.Weekday.String()[:2])
+			if wd.N != 0 {
+				day = strconv.Itoa(wd.N) + day
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	lists = []struct {
+		name   string
+		values []int
+	}{
+		{"BYHOUR", r.ByHour},
+		{"BYMINUTE", r.ByMinute},
+		{"BYSECOND", r.BySecond},
+		{"BYSETPOS", r.BySetPos},
+	}
+	for _, list := range lists {
+		if len(list.values) > 0 {
+			parts = append(parts, list.name+"="+joinInts(list.values))
+		}
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+
+	props := []string{"DTSTART" + formatICalTime(r.Start), "RRULE:" + strings.Join(parts, ";")}
+	for _, ex := range r.Exclude {
+		props = append(props, "EXDATE:"+ex.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(props, " ")
+}
+
+// formatICalTime返回t作为DTSTART的参数和值，例如":20260101T090000Z"或";TZID=Asia/Tokyo:20260101T090000"。
+func formatICalTime(t time.Time) string {
+	const layout = "20060102T150405"
+	switch t.Location() {
+	case time.UTC:
+		return ":" + t.Format(layout) + "Z"
+	case time.Local:
+		return ":" + t.Format(layout)
+	}
+	return ";TZID=" + t.Location().String() + ":" + t.Format(layout)
+}
+
+func joinInts(values []int) string {
+	var s []string
+	for _, v := range values {
+		s = append(s, strconv.Itoa(v))
+	}
+	return strings.Join(s, ",")
+}
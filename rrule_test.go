@@ -0,0 +1,202 @@
+package cron
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Examples from RFC 5545, section 3.8.5.3, evaluated in UTC.
+func TestRRuleNext(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected []string
+	}{
+		{"DTSTART:19970902T090000Z RRULE:FREQ=DAILY;COUNT=10", []string{
+			"19970902T090000Z", "19970903T090000Z", "19970904T090000Z", "19970905T090000Z", "19970906T090000Z",
+			"19970907T090000Z", "19970908T090000Z", "19970909T090000Z", "19970910T090000Z", "19970911T090000Z"}},
+		{"DTSTART:19970902T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=19971008T000000Z;WKST=SU;BYDAY=MO,WE,FR", []string{
+			"19970903T090000Z", "19970905T090000Z", "19970915T090000Z", "19970917T090000Z",
+			"19970919T090000Z", "19970929T090000Z", "19971001T090000Z", "19971003T090000Z"}},
+		{"DTSTART:19970805T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", []string{
+			"19970805T090000Z", "19970810T090000Z", "19970819T090000Z", "19970824T090000Z"}},
+		{"DTSTART:19970805T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", []string{
+			"19970805T090000Z", "19970817T090000Z", "19970819T090000Z", "19970831T090000Z"}},
+		{"DTSTART:19970905T090000Z RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=1FR", []string{
+			"19970905T090000Z", "19971003T090000Z", "19971107T090000Z",
+			"19971205T090000Z", "19980102T090000Z", "19980206T090000Z"}},
+		{"DTSTART:19970922T090000Z RRULE:FREQ=MONTHLY;COUNT=6;BYDAY=-2MO", []string{
+			"19970922T090000Z", "19971020T090000Z", "19971117T090000Z",
+			"19971222T090000Z", "19980119T090000Z", "19980216T090000Z"}},
+		{"DTSTART:19970930T090000Z RRULE:FREQ=MONTHLY;COUNT=5;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", []string{
+			"19970930T090000Z", "19971031T090000Z", "19971128T090000Z", "19971231T090000Z", "19980130T090000Z"}},
+		{"DTSTART:19970904T090000Z RRULE:FREQ=MONTHLY;COUNT=3;BYDAY=TU,WE,TH;BYSETPOS=3", []string{
+			"19970904T090000Z", "19971007T090000Z", "19971106T090000Z"}},
+		// Excluded dates still count towards COUNT.
+		{"DTSTART:19970902T090000Z EXDATE:19980313T090000Z RRULE:FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13;COUNT=4", []string{
+			"19980213T090000Z", "19981113T090000Z", "19990813T090000Z"}},
+		{"DTSTART:19970512T090000Z RRULE:FREQ=YEARLY;BYWEEKNO=20;BYDAY=MO;COUNT=3", []string{
+			"19970512T090000Z", "19980511T090000Z", "19990517T090000Z"}},
+		{"DTSTART:19970101T090000Z RRULE:FREQ=YEARLY;INTERVAL=3;COUNT=10;BYYEARDAY=1,100,200", []string{
+			"19970101T090000Z", "19970410T090000Z", "19970719T090000Z", "20000101T090000Z", "20000409T090000Z",
+			"20000718T090000Z", "20030101T090000Z", "20030410T090000Z", "20030719T090000Z", "20060101T090000Z"}},
+		{"DTSTART:19970313T090000Z RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=TH;COUNT=5", []string{
+			"19970313T090000Z", "19970320T090000Z", "19970327T090000Z", "19980305T090000Z", "19980312T090000Z"}},
+		{"DTSTART:19961105T090000Z RRULE:FREQ=YEARLY;INTERVAL=4;BYMONTH=11;BYDAY=TU;BYMONTHDAY=2,3,4,5,6,7,8;COUNT=3", []string{
+			"19961105T090000Z", "20001107T090000Z", "20041102T090000Z"}},
+		{"DTSTART:19970902T090000Z RRULE:FREQ=HOURLY;INTERVAL=3;UNTIL=19970902T170000Z", []string{
+			"19970902T090000Z", "19970902T120000Z", "19970902T150000Z"}},
+		{"DTSTART:19970902T090000Z RRULE:FREQ=MINUTELY;INTERVAL=20;BYHOUR=9,10;COUNT=7", []string{
+			"19970902T090000Z", "19970902T092000Z", "19970902T094000Z", "19970902T100000Z",
+			"19970902T102000Z", "19970902T104000Z", "19970903T090000Z"}},
+		{"DTSTART:19970902T090000Z RRULE:FREQ=DAILY;BYHOUR=9,10;BYMINUTE=0,30;COUNT=5", []string{
+			"19970902T090000Z", "19970902T093000Z", "19970902T100000Z", "19970902T103000Z", "19970903T090000Z"}},
+		{"DTSTART;VALUE=DATE:19970101 RRULE:FREQ=YEARLY;UNTIL=19990101", []string{
+			"19970101T000000Z", "19980101T000000Z", "19990101T000000Z"}},
+	}
+
+	for _, test := range tests {
+		r, err := parseRRule(test.rule, time.UTC)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.rule, err)
+			continue
+		}
+		var actual []string
+		for next := r.Next(r.Start.Add(-time.Second)); !next.IsZero(); next = r.Next(next) {
+			actual = append(actual, next.UTC().Format("20060102T150405Z"))
+			if len(actual) > len(test.expected) {
+				break
+			}
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s =>\nexpected %v\n     got %v", test.rule, test.expected, actual)
+		}
+	}
+}
+
+func TestRRuleNextFrom(t *testing.T) {
+	// Jumping straight to the period around t gives the same result as iterating.
+	r, err := ParseRRule("DTSTART;TZID=America/New_York:20120101T093000 RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := getTime("TZ=America/New_York 2012-07-09T10:00:00-0400")
+	expected := getTime("TZ=America/New_York 2012-07-13T09:30:00-0400")
+	if actual := r.Next(from); !actual.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	never, _ := ParseRRule("DTSTART:20120101T000000Z RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	if actual := never.Next(from); !actual.IsZero() {
+		t.Errorf("expected the zero time, got %v", actual)
+	}
+}
+
+func TestRRuleNextLargeCount(t *testing.T) {
+	// COUNT is reached by counting activations, however many periods that takes.
+	r, err := ParseRRule("DTSTART:20260101T000000Z RRULE:FREQ=MINUTELY;COUNT=200000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := getTime("2026-01-01T00:00:00-0000")
+	last := start.Add((200000 - 1) * time.Minute)
+	tests := []struct {
+		from, expected time.Time
+	}{
+		{getTime("2026-03-15T00:00:00-0000"), getTime("2026-03-15T00:01:00-0000")},
+		{getTime("2026-03-15T00:00:30-0000"), getTime("2026-03-15T00:01:00-0000")},
+		{last.Add(-time.Second), last},
+		{last, time.Time{}},
+
+		// Earlier times still count from the first period.
+		{start, start.Add(time.Minute)},
+		{getTime("2026-02-01T00:00:00-0000"), getTime("2026-02-01T00:01:00-0000")},
+	}
+	for _, test := range tests {
+		if actual := r.Next(test.from); !actual.Equal(test.expected) {
+			t.Errorf("%v => expected %v, got %v", test.from, test.expected, actual)
+		}
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	tests := []struct {
+		rule   string
+		offset int
+		reason ParseErrorReason
+		err    string
+	}{
+		{"DTSTART:20120101T000000Z", 0, ReasonSyntax, "missing RRULE"},
+		{"RRULE:COUNT=3", 6, ReasonSyntax, "missing FREQ"},
+		{"RRULE:FREQ=FORTNIGHTLY", 6, ReasonSyntax, "unknown frequency"},
+		{"RRULE:FREQ=DAILY;BYHOUR=9,24", 17, ReasonOutOfRange, "out of range"},
+		{"RRULE:FREQ=DAILY;BYMONTHDAY=0", 17, ReasonOutOfRange, "out of range"},
+		{"RRULE:FREQ=WEEKLY;BYDAY=1MO", 6, ReasonUnsupported, "numbered BYDAY"},
+		{"RRULE:FREQ=DAILY;COUNT=3;UNTIL=20120101", 6, ReasonUnsupported, "COUNT and UNTIL"},
+		{"RRULE:FREQ=DAILY;BYFOO=1", 17, ReasonUnsupported, "unsupported rule part"},
+		{"DTSTART:2012-01-01 RRULE:FREQ=DAILY", 8, ReasonSyntax, "failed to parse date-time"},
+		{"DTSTART;TZID=Nowhere/Else:20120101T000000 RRULE:FREQ=DAILY", 26, ReasonBadLocation, "bad location"},
+		{"RRULE:FREQ=DAILY FOO:BAR", 17, ReasonSyntax, "unknown property"},
+	}
+	for _, test := range tests {
+		_, err := ParseRRule(test.rule)
+		var perr *ParseError
+		if !errors.As(err, &perr) || perr.Offset != test.offset || perr.Reason != test.reason || !strings.Contains(perr.Msg, test.err) {
+			t.Errorf("%s => expected %s error %q at %d, got %+v", test.rule, test.reason, test.err, test.offset, err)
+		}
+	}
+}
+
+func TestParseRRuleSpec(t *testing.T) {
+	parser := NewParser(Minute | Hour | Dom | Month | Dow | Descriptor | RRule)
+	sched, err := parser.Parse("CRON_TZ=Asia/Tokyo RRULE:FREQ=DAILY;BYHOUR=9;BYMINUTE=30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := sched.(*RRuleSchedule)
+	if r.Start.Location().String() != "Asia/Tokyo" {
+		t.Errorf("expected the rule to start in Asia/Tokyo, got %v", r.Start.Location())
+	}
+	next := sched.Next(time.Now())
+	if hour, min, _ := next.In(r.Start.Location()).Clock(); hour != 9 || min != 30 {
+		t.Errorf("expected 09:30 in Asia/Tokyo, got %v", next)
+	}
+
+	if _, err := New(WithParser(parser)).AddFunc("DTSTART:20260105T090000Z RRULE:FREQ=WEEKLY;BYDAY=MO,WE", func() {}); err != nil {
+		t.Errorf("AddFunc: unexpected error %v", err)
+	}
+
+	_, err = parser.Parse("CRON_TZ=UTC RRULE:FREQ=DAILY;BYHOUR=25")
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Offset != 29 || perr.Token != "BYHOUR=25" {
+		t.Errorf("expected an error at BYHOUR=25, got %+v", err)
+	}
+
+	// Parsers without the option reject rules.
+	for _, spec := range []string{"RRULE:FREQ=DAILY", "CRON_TZ=UTC DTSTART:20260105T090000Z RRULE:FREQ=DAILY"} {
+		_, err := ParseStandard(spec)
+		if !errors.As(err, &perr) || perr.Reason != ReasonUnsupported {
+			t.Errorf("%s => expected an unsupported error, got %v", spec, err)
+		}
+	}
+}
+
+func TestRRuleString(t *testing.T) {
+	rules := []string{
+		"DTSTART:19970902T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=19971224T000000Z;BYDAY=MO,WE,FR;WKST=SU",
+		"DTSTART;TZID=America/New_York:19970905T090000 RRULE:FREQ=MONTHLY;COUNT=10;BYDAY=1FR,-1FR;BYSETPOS=1 EXDATE:19971003T130000Z",
+		"DTSTART:19970101T000000Z RRULE:FREQ=YEARLY;BYMONTH=3;BYWEEKNO=-1,20;BYYEARDAY=100;BYMONTHDAY=-1;BYHOUR=9;BYMINUTE=30;BYSECOND=15",
+	}
+	for _, rule := range rules {
+		r, err := ParseRRule(rule)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", rule, err)
+			continue
+		}
+		if actual := r.String(); actual != rule {
+			t.Errorf("expected %q, got %q", rule, actual)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package cron
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // SpecSchedule根据传统的crontab 规范指定工作周期（秒粒度）。
 // 它最初进行计算并存储为位集。
@@ -9,6 +12,26 @@ type SpecSchedule struct {
 
 	// 覆盖此时间表的时区
 	Location *time.Location
+
+	// 时间表允许的年份（升序），为nil时表示任意年份。
+	Year []int
+
+	// 时间表在每个匹配的秒内激活的毫秒数（升序），为nil时只在整秒激活。
+	Millisecond []int
+
+	// 夏令时转换中不存在或重复的本地时间的处理方式，零值为DSTSkip|DSTTwice。
+	DST DSTPolicy
+
+	// 为true时日期和星期字段必须同时匹配。为false时采用Vixie cron的规则：
+	// 任一字段为*时二者同时匹配，否则匹配其中之一即可。
+	DomAndDow bool
+
+	// Quartz风格的修饰符，只有在解析器启用了QuartzModifiers时才会设置。
+	//   DomLast:    第i位表示"L-i"，即当月倒数第i天（第0位就是"L"）
+	//   DomWeekday: 第d位表示"dW"，即离d号最近的工作日；第0位表示"LW"
+	//   DowLast:    第d位表示"dL"，即当月最后一个星期d
+	//   DowNth:     第7*(k-1)+d位表示"d#k"，即当月第k个星期d
+	DomLast, DomWeekday, DowLast, DowNth uint64
 }
 
 // bounds提供了一系列可接受的值（以及名称到值的映射）
@@ -46,6 +69,8 @@ var (
 		"fri": 5,
 		"sat": 6,
 	}}
+	years        = bounds{1970, 2099, nil}
+	milliseconds = bounds{0, 999, nil}
 )
 
 const (
@@ -56,6 +81,29 @@ const (
 // Next返回该时间表激活后的下一次时间大于给定时间。
 // 如果找不到满足时间表的时间，则返回时间的零值。
 func (s *SpecSchedule) Next(t time.Time) time.Time {
+	if s.Millisecond == nil {
+		return s.next(t)
+	}
+
+	// 先在t所在的整秒内查找，再使用下一个匹配的整秒中的第一个毫秒数。
+	second := s.next(t.Truncate(time.Second).Add(-time.Nanosecond))
+	if !second.IsZero() && !second.After(t) {
+		for _, ms := range s.Millisecond {
+			if next := second.Add(time.Duration(ms) * time.Millisecond); next.After(t) {
+				return next
+			}
+		}
+		second = s.next(second)
+	}
+	if second.IsZero() {
+		return second
+	}
+	return second.Add(time.Duration(s.Millisecond[0]) * time.Millisecond)
+}
+
+// nextMatch返回晚于t、本地时间匹配时间表的第一个整秒。
+// 不存在的本地时间被跳过，重复的本地时间会匹配两次；DST策略由next处理。
+func (s *SpecSchedule) nextMatch(t time.Time) time.Time {
 	// 一般的做法
 	//
 	// 对于 Month, Day, Hour, Minute, Second:
@@ -82,11 +130,21 @@ func (s *SpecSchedule) Next(t time.Time) time.Time {
 	added := false
 
 	// 如果五年内没有找到时间，则返回零。
+	// 限定了年份的时间表改为在最后一个年份过去后返回零。
 	yearLimit := t.Year() + 5
 
 WRAP:
-	if t.Year() > yearLimit {
-		return time.Time{}
+	if s.Year == nil {
+		if t.Year() > yearLimit {
+			return time.Time{}
+		}
+	} else if year := s.nextYear(t.Year()); year != t.Year() {
+		if year == 0 {
+			return time.Time{}
+		}
+		// 跳到下一个允许的年份，并从该年的开始重新匹配。
+		added = true
+		t = time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
 	}
 
 	// 找到第一个适用月份。
@@ -168,14 +226,194 @@ WRAP:
 	return t.In(origLocation)
 }
 
+// Prev返回不晚于给定时间的最近一次激活时间。
+// 如果找不到满足时间表的时间，则返回时间的零值。
+// 它与Next采用相同的时区和夏令时语义（见DSTPolicy）。
+func (s *SpecSchedule) Prev(t time.Time) time.Time {
+	if s.Millisecond == nil {
+		return s.prev(t)
+	}
+
+	// 从不晚于t的最近一个匹配的整秒开始，取其中不晚于t的最后一个毫秒数；
+	// 如果都晚于t（只可能发生在t所在的整秒），则使用上一个匹配的整秒中的最后一个毫秒数。
+	for second := s.prev(t); !second.IsZero(); second = s.prev(second.Add(-time.Nanosecond)) {
+		for i := len(s.Millisecond) - 1; i >= 0; i-- {
+			if prev := second.Add(time.Duration(s.Millisecond[i]) * time.Millisecond); !prev.After(t) {
+				return prev
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// prevMatch返回不晚于t、本地时间匹配时间表的最后一个整秒，与nextMatch相对应。
+func (s *SpecSchedule) prevMatch(t time.Time) time.Time {
+	// 与Next的做法相反：某个字段不匹配时，将时间设为上一个单位的最后一秒，
+	// 这样更低的字段都从最大值开始向前检查。
+
+	origLocation := t.Location()
+	loc := s.Location
+	if loc == time.Local {
+		loc = t.Location()
+	}
+	if s.Location != time.Local {
+		t = t.In(s.Location)
+	}
+
+	// 从最晚的可能时间（即当前的整秒）开始。
+	t = t.Add(-time.Duration(t.Nanosecond()) * time.Nanosecond)
+
+	// 如果五年内没有找到时间，则返回零。
+	yearLimit := t.Year() - 5
+
+WRAP:
+	if s.Year == nil {
+		if t.Year() < yearLimit {
+			return time.Time{}
+		}
+	} else if year := s.prevYear(t.Year()); year != t.Year() {
+		if year == 0 {
+			return time.Time{}
+		}
+		t = time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc).Add(-1 * time.Second)
+	}
+
+	for 1<<uint(t.Month())&s.Month == 0 {
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-1 * time.Second)
+
+		if t.Month() == time.December {
+			goto WRAP
+		}
+	}
+
+	for !dayMatches(s, t) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-1 * time.Second)
+
+		if t.Day() == daysIn(t.Year(), t.Month()) {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.Hour == 0 {
+		// 减去已经过去的分钟和秒，而不是使用time.Date，以免在重复的小时中选错偏移。
+		t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second()+1)*time.Second)
+
+		if t.Hour() == 23 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.Minute == 0 {
+		t = t.Truncate(time.Minute).Add(-1 * time.Second)
+
+		if t.Minute() == 59 {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.Second == 0 {
+		t = t.Add(-1 * time.Second)
+
+		if t.Second() == 59 {
+			goto WRAP
+		}
+	}
+
+	return t.In(origLocation)
+}
+
+// prevYear返回时间表允许的、不晚于year的最后一个年份，如果没有则返回0。
+func (s *SpecSchedule) prevYear(year int) int {
+	i := sort.SearchInts(s.Year, year+1)
+	if i == 0 {
+		return 0
+	}
+	return s.Year[i-1]
+}
+
+// nextYear返回时间表允许的、不早于year的第一个年份，如果没有则返回0。
+func (s *SpecSchedule) nextYear(year int) int {
+	i := sort.SearchInts(s.Year, year)
+	if i == len(s.Year) {
+		return 0
+	}
+	return s.Year[i]
+}
+
 // 如果给定时间满足时间表的day-of-week和day-of-month限制，Matches返回true
 func dayMatches(s *SpecSchedule, t time.Time) bool {
 	var (
-		domMatch bool = 1<<uint(t.Day())&s.Dom > 0
-		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0
+		domMatch bool = 1<<uint(t.Day())&s.Dom > 0 || domModifierMatches(s, t)
+		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0 || dowModifierMatches(s, t)
 	)
-	if s.Dom&starBit > 0 || s.Dow&starBit > 0 {
+	if s.DomAndDow || s.Dom&starBit > 0 || s.Dow&starBit > 0 {
 		return domMatch && dowMatch
 	}
 	return domMatch || dowMatch
 }
+
+// domModifierMatches返回给定日期是否满足Dom字段中的L、L-n、LW或nW修饰符。
+func domModifierMatches(s *SpecSchedule, t time.Time) bool {
+	if s.DomLast|s.DomWeekday == 0 {
+		return false
+	}
+	var (
+		day  = t.Day()
+		last = daysIn(t.Year(), t.Month())
+	)
+	if 1<<uint(last-day)&s.DomLast > 0 {
+		return true
+	}
+	if s.DomWeekday&1 > 0 && day == nearestWeekday(t.Year(), t.Month(), last, last) {
+		return true
+	}
+	// 离d号最近的工作日最多与d相差两天（例如1号是星期六时为3号）。
+	for d := day - 2; d <= day+2; d++ {
+		if d < int(dom.min) || d > int(dom.max) || 1<<uint(d)&s.DomWeekday == 0 {
+			continue
+		}
+		if nearestWeekday(t.Year(), t.Month(), d, last) == day {
+			return true
+		}
+	}
+	return false
+}
+
+// dowModifierMatches返回给定日期是否满足Dow字段中的dL或d#k修饰符。
+func dowModifierMatches(s *SpecSchedule, t time.Time) bool {
+	if s.DowLast|s.DowNth == 0 {
+		return false
+	}
+	weekday := uint(t.Weekday())
+	if 1<<weekday&s.DowLast > 0 && t.Day()+7 > daysIn(t.Year(), t.Month()) {
+		return true
+	}
+	nth := uint(t.Day()-1) / 7
+	return 1<<(nth*7+weekday)&s.DowNth > 0
+}
+
+// nearestWeekday返回该月中离day号最近的工作日（星期一至星期五），不会跨越月份边界。
+// 如果该月没有day号，则返回0。
+func nearestWeekday(year int, month time.Month, day, last int) int {
+	if day > last {
+		return 0
+	}
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+	return day
+}
+
+// daysIn返回给定月份的天数。
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
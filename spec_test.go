@@ -172,6 +172,17 @@ func TestNext(t *testing.T) {
 		{"TZ=America/New_York 2012-11-04T00:00:00-0400", "0 0 3 * * ?", "2012-11-04T03:00:00-0500"},
 		{"TZ=America/New_York 2012-11-04T03:00:00-0500", "0 0 3 * * ?", "2012-11-05T03:00:00-0500"},
 
+		// Ranges wrapping around the end of the field
+		{"Mon Jul 9 03:00 2012", "0 0 22-2 * * ?", "Mon Jul 9 22:00 2012"},
+		{"Mon Jul 9 23:00 2012", "0 0 22-2 * * ?", "Tue Jul 10 00:00 2012"},
+		{"Tue Jul 10 02:00 2012", "0 0 22-2 * * ?", "Tue Jul 10 22:00 2012"},
+		{"Mon Jul 9 23:00 2012", "0 0 21-4/3 * * ?", "Tue Jul 10 00:00 2012"},
+		{"Tue Jul 10 00:00 2012", "0 0 21-4/3 * * ?", "Tue Jul 10 03:00 2012"},
+		{"Tue Jul 10 00:00 2012", "0 0 0 ? * fri-mon", "Fri Jul 13 00:00 2012"},
+		{"Sun Jul 15 00:00 2012", "0 0 0 ? * fri-mon", "Mon Jul 16 00:00 2012"},
+		{"Mon Jul 9 00:00 2012", "0 0 0 1 Nov-Feb ?", "Thu Nov 1 00:00 2012"},
+		{"Tue Jan 1 00:00 2013", "0 0 0 1 Nov-Feb ?", "Fri Feb 1 00:00 2013"},
+
 		// Unsatisfiable
 		{"Mon Jul 9 23:35 2012", "0 0 0 30 Feb ?", ""},
 		{"Mon Jul 9 23:35 2012", "0 0 0 31 Apr ?", ""},
@@ -199,6 +210,219 @@ func TestNext(t *testing.T) {
 	}
 }
 
+func TestNextQuartzModifiers(t *testing.T) {
+	parser := NewParser(Second | Minute | Hour | Dom | Month | DowOptional | Descriptor | QuartzModifiers)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// Last day of the month
+		{"Thu Jan 15 00:00 2026", "0 0 0 L * ?", "Sat Jan 31 00:00 2026"},
+		{"Sat Jan 31 00:00 2026", "0 0 0 L * ?", "Sat Feb 28 00:00 2026"},
+		{"Thu Jan 15 00:00 2026", "0 0 0 L-2 * ?", "Thu Jan 29 00:00 2026"},
+		{"Fri Jan 16 00:00 2026", "0 0 0 L,15 * ?", "Sat Jan 31 00:00 2026"},
+
+		// Last weekday of the month
+		{"Sun Feb 1 00:00 2026", "0 0 0 LW * ?", "Fri Feb 27 00:00 2026"},
+		{"Fri May 1 00:00 2026", "0 0 0 LW * ?", "Fri May 29 00:00 2026"},
+
+		// Nearest weekday, without crossing the month boundary
+		{"Tue Jan 20 00:00 2026", "0 0 0 1W * ?", "Mon Feb 2 00:00 2026"},
+		{"Fri Jul 10 00:00 2026", "0 0 0 1W * ?", "Mon Aug 3 00:00 2026"},
+		{"Sun Nov 1 00:00 2026", "0 0 0 15W * ?", "Mon Nov 16 00:00 2026"},
+		{"Sat Aug 1 00:00 2026", "0 0 0 15W * ?", "Fri Aug 14 00:00 2026"},
+		{"Fri Jan 1 00:00 2027", "0 0 0 31W * ?", "Fri Jan 29 00:00 2027"},
+		{"Sun Feb 1 00:00 2026", "0 0 0 31W * ?", "Tue Mar 31 00:00 2026"},
+
+		// Last given weekday of the month
+		{"Thu Jan 1 00:00 2026", "0 0 0 ? * 5L", "Fri Jan 30 00:00 2026"},
+		{"Sat Jan 31 00:00 2026", "0 0 0 ? * friL", "Fri Feb 27 00:00 2026"},
+
+		// Last day of the week
+		{"Thu Jan 1 00:00 2026", "0 0 0 ? * L", "Sat Jan 3 00:00 2026"},
+
+		// Nth given weekday of the month
+		{"Thu Jan 1 00:00 2026", "0 0 0 ? * MON#2", "Mon Jan 12 00:00 2026"},
+		{"Mon Jan 12 00:00 2026", "0 0 0 ? * MON#2", "Mon Feb 9 00:00 2026"},
+		{"Thu Jan 1 00:00 2026", "0 0 0 ? * 1#5", "Mon Mar 30 00:00 2026"},
+
+		// Unsatisfiable
+		{"Thu Jan 1 00:00 2026", "0 0 0 30W Feb ?", ""},
+	}
+
+	for _, c := range runs {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
+
+func TestNextDomAndDow(t *testing.T) {
+	tests := []struct {
+		options  ParseOption
+		spec     string
+		time     string
+		expected string
+	}{
+		// Vixie cron matches either field when both are restricted.
+		{0, "0 0 13 * fri", "Sat Jan 14 00:00 2012", "Fri Jan 20 00:00 2012"},
+		{DayAnd, "0 0 13 * fri", "Sat Jan 14 00:00 2012", "Fri Apr 13 00:00 2012"},
+		{DayAnd, "0 0 13 * fri", "Fri Apr 13 00:00 2012", "Fri Jul 13 00:00 2012"},
+		{DayAnd, "0 0 * * fri", "Sat Jan 14 00:00 2012", "Fri Jan 20 00:00 2012"},
+		{DayAnd, "0 0 1-7 * mon", "Sat Jan 14 00:00 2012", "Mon Feb 6 00:00 2012"},
+		{DayAnd | QuartzModifiers, "0 0 L * fri", "Sat Jan 14 00:00 2012", "Fri Aug 31 00:00 2012"},
+		{DayAnd, "0 0 31 2 *", "Sat Jan 14 00:00 2012", ""},
+		{DayQuartz, "0 0 13 * ?", "Sat Jan 14 00:00 2012", "Mon Feb 13 00:00 2012"},
+		{DayQuartz, "0 0 ? * fri", "Sat Jan 14 00:00 2012", "Fri Jan 20 00:00 2012"},
+	}
+	for _, test := range tests {
+		sched, err := NewParser(Minute | Hour | Dom | Month | Dow | test.options).Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		actual := sched.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+	}
+}
+
+func TestNextYear(t *testing.T) {
+	parser := NewParser(Second | Minute | Hour | Dom | Month | Dow | Year)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		{"Fri Oct 16 12:00 2026", "0 0 0 1 1 * 2027-2029", "Fri Jan 1 00:00 2027"},
+		{"Fri Jan 1 00:00 2027", "0 0 0 1 1 * 2027-2029", "Sat Jan 1 00:00 2028"},
+		{"Mon Jan 1 00:00 2029", "0 0 0 1 1 * 2027-2029", ""},
+
+		// One-off schedule more than five years ahead
+		{"Fri Oct 16 12:00 2026", "0 30 9 4 Jul * 2040", "Wed Jul 4 09:30 2040"},
+
+		// Within the current year
+		{"Fri Oct 16 12:00 2026", "0 0 0 * * * 2026,2030", "Sat Oct 17 00:00 2026"},
+		{"Thu Dec 31 12:00 2026", "0 0 0 * * * 2026,2030", "Tue Jan 1 00:00 2030"},
+
+		// Unsatisfiable within the allowed years
+		{"Fri Oct 16 12:00 2026", "0 0 0 29 Feb * 2027-2031/2", ""},
+		{"Fri Oct 16 12:00 2026", "0 0 0 29 Feb * 2027-2028", "Tue Feb 29 00:00 2028"},
+	}
+
+	for _, c := range runs {
+		sched, err := parser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.Next(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+}
+
+func TestPrev(t *testing.T) {
+	yearParser := NewParser(Second | Minute | Hour | Dom | Month | Dow | YearOptional | QuartzModifiers)
+	runs := []struct {
+		time, spec string
+		expected   string
+	}{
+		// Simple cases
+		{"Mon Jul 9 15:10 2012", "0 0/15 * * * *", "Mon Jul 9 15:00 2012"},
+		{"Mon Jul 9 15:00 2012", "0 0/15 * * * *", "Mon Jul 9 15:00 2012"},
+		{"Mon Jul 9 14:59:59 2012", "0 0/15 * * * *", "Mon Jul 9 14:45 2012"},
+		{"Mon Jul 9 15:00:00.5 2012", "* * * * * *", "Mon Jul 9 15:00 2012"},
+
+		// Wrap around hours, days, months and years
+		{"Mon Jul 9 16:10 2012", "0 20-35/15 * * * *", "Mon Jul 9 15:35 2012"},
+		{"Tue Jul 10 00:10 2012", "0 20-35/15 * * * *", "Mon Jul 9 23:35 2012"},
+		{"Tue Jul 10 00:10 2012", "15/35 20-35/15 10-12 * * *", "Mon Jul 9 12:35:50 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 9 Apr-Oct ?", "Mon Jul 9 00:00 2012"},
+		{"Sun Jul 8 23:35 2012", "0 0 0 9 Apr-Oct ?", "Sat Jun 9 00:00 2012"},
+		{"Mon Jul 9 23:35 2012", "0 0 0 * Feb Mon", "Mon Feb 27 00:00 2012"},
+		{"Tue Jan 1 00:00 2013", "0 59 23 31 Dec ?", "Mon Dec 31 23:59 2012"},
+
+		// Leap year
+		{"Mon Jul 9 23:35 2012", "0 0 0 29 Feb ?", "Wed Feb 29 00:00 2012"},
+		{"Tue Jan 1 00:00 2013", "0 0 0 29 Feb ?", "Wed Feb 29 00:00 2012"},
+
+		// Daylight savings time 2am EST (-5) -> 3am EDT (-4)
+		{"2012-03-12T00:00:00-0400", "TZ=America/New_York 0 30 2 11 Mar ?", "2011-03-11T02:30:00-0500"},
+		{"2012-03-11T12:00:00-0400", "TZ=America/New_York 0 0 2 * * ?", "2012-03-10T02:00:00-0500"},
+		{"2012-03-11T03:30:00-0400", "TZ=America/New_York 0 0 * * * ?", "2012-03-11T03:00:00-0400"},
+		{"2012-03-11T02:59:59-0400", "TZ=America/New_York 0 0 * * * ?", "2012-03-11T01:00:00-0500"},
+
+		// Daylight savings time 2am EDT (-4) => 1am EST (-5)
+		{"2012-11-04T01:30:00-0500", "TZ=America/New_York 0 0 * * * ?", "2012-11-04T01:00:00-0500"},
+		{"2012-11-04T00:59:59-0500", "TZ=America/New_York 0 0 * * * ?", "2012-11-04T01:00:00-0400"},
+		{"2012-11-04T03:00:00-0500", "TZ=America/New_York 0 0 1 * * ?", "2012-11-04T01:00:00-0500"},
+
+		// Midnight that does not exist
+		{"2018-11-10T12:00:00-0200", "TZ=America/Sao_Paulo 0 0 9 10 * ?", "2018-11-10T09:00:00-0200"},
+
+		// Unsatisfiable
+		{"Mon Jul 9 23:35 2012", "0 0 0 30 Feb ?", ""},
+	}
+
+	for _, c := range runs {
+		sched, err := secondParser.Parse(c.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		actual := sched.(PrevSchedule).Prev(getTime(c.time))
+		expected := getTime(c.expected)
+		if !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", c.time, c.spec, expected, actual)
+		}
+	}
+
+	// Prev must be the inverse of Next: the activation that Next reports for t
+	// is its own Prev, and nothing activates between t and that activation.
+	specs := []string{
+		"0 0/15 * * * *",
+		"15/35 20-35/15 1/2 */2 * *",
+		"0 0 0 L * ?",
+		"0 0 12 15W * ?",
+		"0 0 0 ? * fri#3",
+		"0 0 22-2 * * mon-fri",
+		"0 0 0 1 1 * 2014,2017",
+		"TZ=America/New_York 0 30 1,2 * * ?",
+		"TZ=Asia/Kolkata 0 0 * * * ?",
+	}
+	for _, spec := range specs {
+		sched, err := yearParser.Parse(spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		prev := sched.(PrevSchedule)
+		for from := getTime("Mon Jan 2 15:04 2012"); from.Year() < 2014; from = from.Add(37*time.Hour + 13*time.Second) {
+			next := sched.Next(from)
+			if actual := prev.Prev(next); !actual.Equal(next) {
+				t.Errorf("%s: Prev(%v) = %v, expected itself", spec, next, actual)
+			}
+			last := prev.Prev(next.Add(-time.Second))
+			if last.After(from) {
+				t.Errorf("%s: Prev(%v) = %v, expected at or before %v", spec, next.Add(-time.Second), last, from)
+			}
+			if !last.IsZero() && !sched.Next(last).Equal(next) {
+				t.Errorf("%s: Next(%v) = %v, expected %v", spec, last, sched.Next(last), next)
+			}
+		}
+	}
+}
+
 func TestErrors(t *testing.T) {
 	invalidSpecs := []string{
 		"xyz",
@@ -298,3 +522,80 @@ func TestSlash0NoHang(t *testing.T) {
 		t.Error("expected an error on 0 increment")
 	}
 }
+
+func TestMilliseconds(t *testing.T) {
+	parser := NewParser(MillisecondOptional | Second | Minute | Hour | Dom | Month | Dow)
+	at := func(min, sec, ms int) time.Time {
+		return time.Date(2012, time.July, 9, 14, min, sec, ms*int(time.Millisecond), time.Local)
+	}
+	tests := []struct {
+		spec           string
+		time           time.Time
+		next, previous time.Time
+	}{
+		{"*/250 * * * * * *", at(45, 0, 100), at(45, 0, 250), at(45, 0, 0)},
+		{"*/250 * * * * * *", at(45, 0, 250), at(45, 0, 500), at(45, 0, 250)},
+		{"*/250 * * * * * *", at(45, 0, 750), at(45, 1, 0), at(45, 0, 750)},
+		{"*/250 * * * * * *", at(45, 0, 999), at(45, 1, 0), at(45, 0, 750)},
+		{"100,900 30 * * * * *", at(45, 29, 0), at(45, 30, 100), at(44, 30, 900)},
+		{"100,900 30 * * * * *", at(45, 30, 500), at(45, 30, 900), at(45, 30, 100)},
+		{"100,900 30 * * * * *", at(45, 30, 950), at(46, 30, 100), at(45, 30, 900)},
+		{"100,900 30 * * * * *", at(46, 30, 50), at(46, 30, 100), at(45, 30, 900)},
+
+		// Without the optional field, schedules stay on whole seconds.
+		{"30 * * * * *", at(45, 29, 500), at(45, 30, 0), at(44, 30, 0)},
+		{"0 30 * * * * *", at(45, 29, 500), at(45, 30, 0), at(44, 30, 0)},
+	}
+	for _, test := range tests {
+		schedule, err := parser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		spec := schedule.(*SpecSchedule)
+		if actual := spec.Next(test.time); !actual.Equal(test.next) {
+			t.Errorf("%s, %v: next (expected) %v != %v (actual)", test.spec, test.time, test.next, actual)
+		}
+		if actual := spec.Prev(test.time); !actual.Equal(test.previous) {
+			t.Errorf("%s, %v: prev (expected) %v != %v (actual)", test.spec, test.time, test.previous, actual)
+		}
+	}
+
+	// A millisecond field of 0 is the same as leaving it out.
+	whole, _ := parser.Parse("0 30 * * * * *")
+	if ms := whole.(*SpecSchedule).Millisecond; ms != nil {
+		t.Errorf("expected no milliseconds, got %v", ms)
+	}
+
+	_, err := parser.Parse("1000 * * * * * *")
+	if perr, ok := err.(*ParseError); !ok || perr.Reason != ReasonOutOfRange || perr.Field != "millisecond" || perr.Offset != 0 {
+		t.Errorf("expected an out of range millisecond, got %v", err)
+	}
+}
+
+func TestMillisecondString(t *testing.T) {
+	parser := NewParser(Millisecond | Second | Minute | Hour | Dom | Month | Dow | YearOptional)
+	tests := []struct {
+		spec, expected string
+	}{
+		{"*/250 * * * * * *", "*/250 * * * * * * *"},
+		{"100,900 30 0 9 * * 1-5 2027", "100,900 30 0 9 * * 1-5 2027"},
+	}
+	for _, test := range tests {
+		schedule, err := parser.Parse(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		spec := schedule.(*SpecSchedule)
+		if actual := spec.String(); actual != test.expected {
+			t.Errorf("%s => expected %q, got %q", test.spec, test.expected, actual)
+		}
+		var reparsed SpecSchedule
+		if err := reparsed.UnmarshalText([]byte(spec.String())); err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+		} else if !equalSpecSchedules(spec, &reparsed) {
+			t.Errorf("%s => round trip gave %v", test.spec, reparsed.String())
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package cron
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// SystemdParser解析systemd的日历事件表达式（即systemd.time(7)中OnCalendar=的取值）：
+//   [星期] [年-月-日] [时:分[:秒]] [时区]
+// 例如"Mon..Fri *-*-* 09:00:00"、"*-*-01 04:00"或"Sat,Sun 10:00 Europe/Berlin"。
+//
+// 每个分量可以是"*"、数值、"a..b"形式的范围或用逗号分隔的列表，并可带"/step"步长。
+// 日期中用"~"代替"-"表示从月末倒数，例如"*-02~01"为二月的最后一天，
+// "Mon *-05~07/1"为五月的最后一个星期一。省略的日期为"*-*-*"，省略的时刻为"00:00:00"，
+// 省略的秒为0；时区默认为time.Local。
+// 同时给出星期和日期时，两者都必须满足（不同于crontab中的"或"）。
+//
+// 还接受minutely、hourly、daily、weekly、monthly、quarterly、semiannually、yearly和annually等简写。
+//
+// SystemdParser可以通过WithParser传给New：
+//
+//  c := cron.New(cron.WithParser(cron.SystemdParser{}))
+//  c.AddFunc("Mon..Fri 09:00", func() { ... })
+//
+type SystemdParser struct{}
+
+// Parse返回代表给定日历事件表达式的时间表。返回的错误都是*ParseError。
+func (SystemdParser) Parse(spec string) (Schedule, error) {
+	schedule, err := parseCalendarEvent(spec)
+	if perr, ok := err.(*ParseError); ok {
+		perr.Spec = spec
+	}
+	return schedule, err
+}
+
+// ParseOnCalendar是SystemdParser{}.Parse的简写。
+func ParseOnCalendar(spec string) (Schedule, error) {
+	return SystemdParser{}.Parse(spec)
+}
+
+// calendarShorthands是systemd预定义的日历事件简写。
+var calendarShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+}
+
+var weekdayNames = strings.NewReplacer(
+	"sunday", "sun",
+	"monday", "mon",
+	"tuesday", "tue",
+	"wednesday", "wed",
+	"thursday", "thu",
+	"friday", "fri",
+	"saturday", "sat",
+)
+
+// parseCalendarEvent实现了SystemdParser.Parse。
+func parseCalendarEvent(spec string) (Schedule, error) {
+	fields, offsets := splitFields(spec)
+	if len(fields) == 0 {
+		return nil, parseErrorf(ReasonFieldCount, "empty spec string")
+	}
+
+	// 最后一个以字母开头的字段（如果不止一个字段）是时区。
+	loc := time.Local
+	if n := len(fields); n > 1 && isLetter(fields[n-1][0]) {
+		var err error
+		if loc, err = time.LoadLocation(fields[n-1]); err != nil {
+			return nil, atToken(parseErrorf(ReasonBadLocation, "provided bad location %s: %v", fields[n-1], err), fields[n-1], offsets[n-1])
+		}
+		fields, offsets = fields[:n-1], offsets[:n-1]
+	}
+
+	// 简写展开为等价的表达式，展开后的错误都定位到简写上。
+	if expansion, ok := calendarShorthands[strings.ToLower(fields[0])]; ok {
+		if len(fields) > 1 {
+			return nil, atToken(parseErrorf(ReasonFieldCount, "unexpected field after %s: %s", fields[0], fields[1]), fields[1], offsets[1])
+		}
+		offset := offsets[0]
+		fields, offsets = strings.Fields(expansion), nil
+		for range fields {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	var (
+		weekday                       string
+		date, clock                   = "*-*-*", "00:00:00"
+		weekdayOff, dateOff, clockOff int
+		i                             int
+	)
+	if i < len(fields) && isLetter(fields[i][0]) {
+		weekday, weekdayOff = fields[i], offsets[i]
+		i++
+	}
+	if i < len(fields) && !strings.Contains(fields[i], ":") {
+		date, dateOff = fields[i], offsets[i]
+		i++
+	}
+	if i < len(fields) && strings.Contains(fields[i], ":") {
+		clock, clockOff = fields[i], offsets[i]
+		i++
+	}
+	if i < len(fields) {
+		return nil, atToken(parseErrorf(ReasonFieldCount, "unexpected field: %s", fields[i]), fields[i], offsets[i])
+	}
+
+	// systemd要求日期和星期同时满足。
+	schedule := &SpecSchedule{Location: loc, Dow: all(dow), DomAndDow: true}
+	if weekday != "" {
+		if err := parseCalendarWeekdays(weekday, weekdayOff, schedule); err != nil {
+			return nil, err
+		}
+	}
+	if err := parseCalendarDate(date, dateOff, schedule); err != nil {
+		return nil, err
+	}
+	if err := parseCalendarTime(clock, clockOff, schedule); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// parseCalendarWeekdays解析星期分量，例如"Mon..Fri"或"Sat,Sun"，并设置s.Dow。
+func parseCalendarWeekdays(field string, offset int, s *SpecSchedule) error {
+	var err error
+	s.Dow, err = getCalendarField(field, offset, dow, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.,-", 5)
+	return err
+}
+
+// parseCalendarDate解析日期分量：
+//   [ year "-" ] month "-" day
+//   [ year "-" ] month "~" day
+// 并设置s的Year、Month和Dom（或DomLast）。
+func parseCalendarDate(field string, offset int, s *SpecSchedule) error {
+	sep := strings.LastIndexAny(field, "-~")
+	if sep < 0 {
+		return atToken(parseErrorf(ReasonSyntax, "expected [year-]month-day: %s", field), field, offset)
+	}
+	var (
+		head, day  = field[:sep], field[sep+1:]
+		dayOff     = offset + sep + 1
+		fromEnd    = field[sep] == '~'
+		components = strings.Split(head, "-")
+	)
+	if len(components) > 2 || strings.Contains(head, "~") {
+		return atToken(parseErrorf(ReasonSyntax, "expected [year-]month-day: %s", field), field, offset)
+	}
+
+	var year, month = "*", components[0]
+	monthOff := offset
+	if len(components) == 2 {
+		year, month = components[0], components[1]
+		monthOff += len(year) + 1
+	}
+
+	var err error
+	if s.Year, err = getCalendarYears(year, offset); err != nil {
+		return err
+	}
+	if s.Month, err = getCalendarField(month, monthOff, months, calendarDigits, 4); err != nil {
+		return err
+	}
+	if !fromEnd {
+		s.Dom, err = getCalendarField(day, dayOff, dom, calendarDigits, 3)
+		return err
+	}
+	return getCalendarLastDays(day, dayOff, s)
+}
+
+// getCalendarLastDays解析"~"之后的日分量，例如"01"、"03"或"07/1"，并设置s.DomLast。
+// "~d/step"表示从倒数第d天开始，每隔step天直到月末。
+func getCalendarLastDays(field string, offset int, s *SpecSchedule) error {
+	return calendarItems(field, offset, calendarDigits, 3, func(expr string) error {
+		if strings.ContainsAny(expr, "*-") {
+			return parseErrorf(ReasonUnsupported, "only a day and an optional step may follow ~: %s", expr)
+		}
+		start, _, step, _, err := parseRange(expr, dom, nil)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(expr, "/") {
+			step = start
+		}
+		for d := int(start); d >= 1; d -= int(step) {
+			s.DomLast |= 1 << uint(d-1)
+		}
+		return nil
+	})
+}
+
+// parseCalendarTime解析时刻分量：
+//   hour ":" minute [ ":" second ]
+// 并设置s的Hour、Minute和Second。
+func parseCalendarTime(field string, offset int, s *SpecSchedule) error {
+	components := strings.Split(field, ":")
+	if len(components) > 3 {
+		return atToken(parseErrorf(ReasonSyntax, "expected hour:minute[:second]: %s", field), field, offset)
+	}
+	if len(components) == 2 {
+		components = append(components, "0")
+	}
+
+	var (
+		err    error
+		places = []int{2, 1, 0}
+		ranges = []bounds{hours, minutes, seconds}
+		fields = []*uint64{&s.Hour, &s.Minute, &s.Second}
+	)
+	for i, component := range components {
+		if *fields[i], err = getCalendarField(component, offset, ranges[i], calendarDigits, places[i]); err != nil {
+			return err
+		}
+		offset += len(component) + 1
+	}
+	return nil
+}
+
+// getCalendarYears解析年分量，返回nil表示任意年份。
+func getCalendarYears(field string, offset int) ([]int, error) {
+	var (
+		result  []int
+		anyYear bool
+	)
+	err := calendarItems(field, offset, calendarDigits, 6, func(expr string) error {
+		matched, err := getYearField(expr)
+		if matched == nil {
+			anyYear = true
+		}
+		result = append(result, matched...)
+		return err
+	})
+	if err != nil || anyYear {
+		return nil, err
+	}
+
+	sort.Ints(result)
+	unique := result[:0]
+	for i, y := range result {
+		if i == 0 || y != result[i-1] {
+			unique = append(unique, y)
+		}
+	}
+	return unique, nil
+}
+
+const calendarDigits = "0123456789*./,"
+
+// getCalendarField解析systemd风格的分量并返回对应的位，place是该分量对应的字段位置（见fieldNames）。
+func getCalendarField(field string, offset int, r bounds, allowed string, place int) (uint64, error) {
+	var bits uint64
+	err := calendarItems(field, offset, allowed, place, func(expr string) error {
+		bit, err := getRange(expr, r, nil)
+		bits |= bit
+		return err
+	})
+	return bits, err
+}
+
+// calendarItems校验field中只包含allowed中的字符，然后对其中用逗号分隔的每一项调用fn。
+// 传给fn的项已转换为crontab的写法（"a..b"换成"a-b"，星期的全称换成缩写）；
+// fn返回的错误会被定位到原始的项上，并标记为place处的字段。
+func calendarItems(field string, offset int, allowed string, place int, fn func(expr string) error) error {
+	locate := func(err error, token string, offset int) error {
+		if perr, ok := err.(*ParseError); ok {
+			perr.Field = fieldNames[place]
+			perr.Token, perr.Offset, perr.Length = token, offset, len(token)
+		}
+		return err
+	}
+	if i := strings.IndexFunc(field, func(r rune) bool { return !strings.ContainsRune(allowed, r) }); i >= 0 {
+		return locate(parseErrorf(ReasonSyntax, "unexpected character %q: %s", field[i], field), field, offset)
+	}
+	start := offset
+	for _, item := range strings.Split(field, ",") {
+		if item == "" {
+			return locate(parseErrorf(ReasonSyntax, "empty list item: %s", field), field, start)
+		}
+		expr := weekdayNames.Replace(strings.ToLower(strings.Replace(item, "..", "-", 1)))
+		if err := fn(expr); err != nil {
+			return locate(err, item, offset)
+		}
+		offset += len(item) + 1
+	}
+	return nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,115 @@
+package cron
+
+import (
+	"testing"
+)
+
+func TestParseOnCalendar(t *testing.T) {
+	tests := []struct {
+		spec     string
+		time     string
+		expected string
+	}{
+		{"Mon..Fri *-*-* 09:00:00", "Sat Jul 7 12:00 2012", "Mon Jul 9 09:00 2012"},
+		{"Mon..Fri *-*-* 09:00:00", "Mon Jul 9 09:00 2012", "Tue Jul 10 09:00 2012"},
+		{"*-*-01 04:00", "Mon Jul 9 12:00 2012", "Wed Aug 1 04:00 2012"},
+		{"*:0/15", "Mon Jul 9 12:07 2012", "Mon Jul 9 12:15 2012"},
+		{"*-*-* *:*:30", "Mon Jul 9 12:07 2012", "Mon Jul 9 12:07:30 2012"},
+		{"*-1..3-1/10", "Mon Jul 9 12:00 2012", "Tue Jan 1 00:00 2013"},
+		{"2013-01-01 00:00", "Mon Jul 9 12:00 2012", "Tue Jan 1 00:00 2013"},
+		{"2013-01-01 00:00", "Tue Jan 1 00:00 2013", ""},
+		{"2012,2014-07-09", "Mon Jul 9 12:00 2012", "Wed Jul 9 00:00 2014"},
+
+		// Weekday names, ranges and lists.
+		{"Sat,Sun 10:00", "Mon Jul 9 12:00 2012", "Sat Jul 14 10:00 2012"},
+		{"Saturday..Sunday 10:00", "Sat Jul 14 12:00 2012", "Sun Jul 15 10:00 2012"},
+		{"mon-wed", "Mon Jul 9 12:00 2012", "Tue Jul 10 00:00 2012"},
+
+		// The weekday and the date must both match.
+		{"Fri *-*-13", "Sat Jul 14 00:00 2012", "Fri Sep 13 00:00 2013"},
+
+		// Days counted from the end of the month.
+		{"*-02~01", "Mon Jul 9 12:00 2012", "Thu Feb 28 00:00 2013"},
+		{"*-02~03", "Mon Jul 9 12:00 2012", "Tue Feb 26 00:00 2013"},
+		{"2012-02~01", "Sun Jan 1 00:00 2012", "Wed Feb 29 00:00 2012"},
+		{"Mon *-05~07/1", "Mon Jul 9 12:00 2012", "Mon May 27 00:00 2013"},
+
+		// Shorthands.
+		{"minutely", "Mon Jul 9 12:07 2012", "Mon Jul 9 12:08 2012"},
+		{"hourly", "Mon Jul 9 12:07 2012", "Mon Jul 9 13:00 2012"},
+		{"daily", "Mon Jul 9 12:07 2012", "Tue Jul 10 00:00 2012"},
+		{"weekly", "Mon Jul 9 12:00 2012", "Mon Jul 16 00:00 2012"},
+		{"monthly", "Mon Jul 9 12:00 2012", "Wed Aug 1 00:00 2012"},
+		{"quarterly", "Mon Jul 9 12:00 2012", "Mon Oct 1 00:00 2012"},
+		{"semiannually", "Mon Jul 9 12:00 2012", "Tue Jan 1 00:00 2013"},
+		{"Yearly", "Mon Jul 9 12:00 2012", "Tue Jan 1 00:00 2013"},
+
+		// Time zones.
+		{"daily UTC", "2012-07-09T12:00:00-0000", "2012-07-10T00:00:00-0000"},
+		{"Mon 12:00 Europe/Berlin", "2012-07-09T12:00:00+0200", "TZ=Europe/Berlin 2012-07-16T12:00:00+0200"},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseOnCalendar(test.spec)
+		if err != nil {
+			t.Errorf("%s => unexpected error %v", test.spec, err)
+			continue
+		}
+		actual := schedule.Next(getTime(test.time))
+		if expected := getTime(test.expected); !actual.Equal(expected) {
+			t.Errorf("%s, \"%s\": (expected) %v != %v (actual)", test.spec, test.time, expected, actual)
+		}
+	}
+}
+
+func TestParseOnCalendarErrors(t *testing.T) {
+	tests := []struct {
+		spec   string
+		reason ParseErrorReason
+		field  string
+		token  string
+		offset int
+	}{
+		{"", ReasonFieldCount, "", "", 0},
+		{"Mon *-*-* 25:00", ReasonOutOfRange, "hour", "25", 10},
+		{"*-*-* 12:60:00", ReasonOutOfRange, "minute", "60", 9},
+		{"*-13-01", ReasonOutOfRange, "month", "13", 2},
+		{"1969-01-01", ReasonOutOfRange, "year", "1969", 0},
+		{"Funday 09:00", ReasonSyntax, "day of week", "Funday", 0},
+		{"*-*-* 1-5:00", ReasonSyntax, "hour", "1-5", 6},
+		{"*-*-1,,2", ReasonSyntax, "day of month", "1,,2", 4},
+		{"*-02~*", ReasonUnsupported, "day of month", "*", 5},
+		{"2012", ReasonSyntax, "", "2012", 0},
+		{"*-*-* 1:2:3:4", ReasonSyntax, "", "1:2:3:4", 6},
+		{"daily Mars/Olympus", ReasonBadLocation, "", "Mars/Olympus", 6},
+		{"daily 10:00", ReasonFieldCount, "", "10:00", 6},
+		{"*-*-* 10:00 *-*-*", ReasonFieldCount, "", "*-*-*", 12},
+	}
+
+	for _, test := range tests {
+		_, err := ParseOnCalendar(test.spec)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q => expected a *ParseError, got %v", test.spec, err)
+			continue
+		}
+		if perr.Reason != test.reason || perr.Field != test.field || perr.Token != test.token || perr.Offset != test.offset {
+			t.Errorf("%q => expected %v %q %q at %d, got %v %q %q at %d (%v)", test.spec,
+				test.reason, test.field, test.token, test.offset,
+				perr.Reason, perr.Field, perr.Token, perr.Offset, perr)
+		}
+		if perr.Spec != test.spec {
+			t.Errorf("%q => expected the spec on the error, got %q", test.spec, perr.Spec)
+		}
+	}
+}
+
+func TestSystemdParserWithCron(t *testing.T) {
+	cron := New(WithParser(SystemdParser{}))
+	if _, err := cron.AddFunc("Mon..Fri 09:00", func() {}); err != nil {
+		t.Error(err)
+	}
+	if _, err := cron.AddFunc("0 9 * * 1-5", func() {}); err == nil {
+		t.Error("expected an error for a crontab spec")
+	}
+}